@@ -0,0 +1,28 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fedotovmax/kafka-lib/adapters"
+	"github.com/fedotovmax/kafka-lib/outbox"
+)
+
+const cancelAggregateEventsQuery = `update events set status = $1, reserved_to = NULL, reserved_by = NULL
+where aggregate_id = $2 AND status = $3 AND (reserved_to IS NULL OR reserved_to < $4);`
+
+func (p *postgres) CancelAggregateEvents(ctx context.Context, aggregateID string) (int, error) {
+
+	const op = "adapter.db.postgres.CancelAggregateEvents"
+
+	tx := p.ex.ExtractTx(ctx)
+
+	tag, err := tx.Exec(ctx, cancelAggregateEventsQuery, outbox.EventStatusCancelled, aggregateID, outbox.EventStatusNew, time.Now().UTC())
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kafkalib "github.com/fedotovmax/kafka-lib"
+	"github.com/fedotovmax/kafka-lib/adapters"
+	"github.com/fedotovmax/kafka-lib/outbox"
+)
+
+// exists is evaluated on statement snapshot, so it is true for just cancelled event too.
+// Event with lapsed reservation is free as in ReserveNewEvents, its stale owner can not confirm it after cancel
+const cancelEventQuery = `with cancelled as (
+	update events set status = $1, reserved_to = NULL, reserved_by = NULL
+	where id = $2 AND status = $3 AND (reserved_to IS NULL OR reserved_to < $4)
+	returning id
+)
+select exists(select 1 from cancelled), exists(select 1 from events where id = $2);`
+
+// CancelEvent returns ErrNotFound, if there is no event, and ErrCancelTooLate, if event is already sent or reserved
+func (p *postgres) CancelEvent(ctx context.Context, id string) error {
+
+	const op = "adapter.db.postgres.CancelEvent"
+
+	tx := p.ex.ExtractTx(ctx)
+
+	row := tx.QueryRow(ctx, cancelEventQuery, outbox.EventStatusCancelled, id, outbox.EventStatusNew, time.Now().UTC())
+
+	var cancelled, found bool
+
+	err := row.Scan(&cancelled, &found)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+	}
+
+	if !found {
+		return fmt.Errorf("%s: %w", op, adapters.ErrNotFound)
+	}
+
+	if !cancelled {
+		return fmt.Errorf("%s: %w", op, kafkalib.ErrCancelTooLate)
+	}
+
+	return nil
+}
@@ -2,30 +2,103 @@ package events
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	kafkalib "github.com/fedotovmax/kafka-lib"
 	"github.com/fedotovmax/kafka-lib/adapters"
 	"github.com/fedotovmax/kafka-lib/outbox"
+	"github.com/jackc/pgx/v5"
 )
 
-const createEventQuery = `insert into events (aggregate_id, event_topic, event_type, payload, created_at)
-values ($1,$2,$3,$4,$5) returning id;`
+const createEventQuery = `insert into events (aggregate_id, event_topic, event_type, payload, created_at,
+trace_context, headers, partition_key, event_partition, deliver_after, expires_at, idempotency_key)
+values ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+returning id;`
 
+// requires unique index idx_events_idempotency_key
+const createEventIdempotentQuery = `insert into events (aggregate_id, event_topic, event_type, payload, created_at,
+trace_context, headers, partition_key, event_partition, deliver_after, expires_at, idempotency_key)
+values ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+on conflict (idempotency_key) where idempotency_key is not null do nothing
+returning id;`
+
+const findEventIDByIdempotencyKeyQuery = "select id from events where idempotency_key = $1;"
+
+const notifyEventCreatedQuery = "select pg_notify($1, $2);"
+
+// CreateEvent returns id of existing event and ErrAlreadyExists, if event with same idempotency key exists
 func (p *postgres) CreateEvent(ctx context.Context, in *outbox.CreateEvent) (string, error) {
 	const op = "adapter.db.postgres.CreateEvent"
 
 	tx := p.ex.ExtractTx(ctx)
 
-	row := tx.QueryRow(ctx, createEventQuery,
-		in.GetAggregateID(), in.GetTopic(), in.GetType(), in.GetPayload(), in.GetCreatedAt())
+	query := createEventQuery
+
+	if in.GetIdempotencyKey() != "" {
+		query = createEventIdempotentQuery
+	}
+
+	row := tx.QueryRow(ctx, query, createEventArgs(in)...)
 
 	var id string
 
 	err := row.Scan(&id)
 
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = tx.QueryRow(ctx, findEventIDByIdempotencyKeyQuery, in.GetIdempotencyKey()).Scan(&id)
+
+		if err != nil {
+			return "", fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+		}
+
+		return id, fmt.Errorf("%s: %w", op, kafkalib.ErrAlreadyExists)
+	}
+
 	if err != nil {
 		return "", fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
 	}
 
+	if p.notifyChannel != "" {
+		_, err = tx.Exec(ctx, notifyEventCreatedQuery, p.notifyChannel, id)
+
+		if err != nil {
+			return "", fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+		}
+	}
+
 	return id, nil
 }
+
+// createEventArgs returns insert args, optional fields are passed as null
+func createEventArgs(in *outbox.CreateEvent) []any {
+
+	var traceContext, headers, partitionKey, deliverAfter, expiresAt, idempotencyKey any
+
+	if len(in.GetTraceContext()) > 0 {
+		traceContext = in.GetTraceContext()
+	}
+
+	if len(in.GetHeaders()) > 0 {
+		headers = in.GetHeaders()
+	}
+
+	if in.GetPartitionKey() != "" {
+		partitionKey = in.GetPartitionKey()
+	}
+
+	if !in.GetDeliverAfter().IsZero() {
+		deliverAfter = in.GetDeliverAfter().UTC()
+	}
+
+	if !in.GetExpiresAt().IsZero() {
+		expiresAt = in.GetExpiresAt().UTC()
+	}
+
+	if in.GetIdempotencyKey() != "" {
+		idempotencyKey = in.GetIdempotencyKey()
+	}
+
+	return []any{in.GetAggregateID(), in.GetTopic(), in.GetType(), in.GetPayload(), in.GetCreatedAt(),
+		traceContext, headers, partitionKey, in.GetPartition(), deliverAfter, expiresAt, idempotencyKey}
+}
@@ -0,0 +1,248 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	kafkalib "github.com/fedotovmax/kafka-lib"
+	"github.com/fedotovmax/kafka-lib/adapters"
+	"github.com/fedotovmax/kafka-lib/outbox"
+)
+
+const createEventsInputCTE = `with input as (
+  select gen_random_uuid() as id, t.*
+  from unnest($1::varchar[], $2::varchar[], $3::varchar[], $4::text[], $5::timestamp[],
+  $6::text[], $7::text[], $8::varchar[], $9::int[], $10::timestamp[], $11::timestamp[], $12::varchar[])
+  with ordinality as t(aggregate_id, event_topic, event_type, payload, created_at,
+  trace_context, headers, partition_key, event_partition, deliver_after, expires_at, idempotency_key, ord)
+), inserted as (
+  insert into events (id, aggregate_id, event_topic, event_type, payload, created_at,
+  trace_context, headers, partition_key, event_partition, deliver_after, expires_at, idempotency_key)
+  select id, aggregate_id, event_topic, event_type, payload::jsonb, created_at,
+  trace_context::jsonb, headers::jsonb, partition_key, event_partition, deliver_after, expires_at, idempotency_key
+  from input order by ord
+`
+
+// ids are generated before insert to match returned rows with input order
+const createEventsQuery = createEventsInputCTE + `  returning id
+)
+select n.id::text
+from input i
+join inserted n on n.id = i.id
+order by i.ord;`
+
+// requires unique index idx_events_idempotency_key, id is null for rows skipped by conflict
+const createEventsIdempotentQuery = createEventsInputCTE + `  on conflict (idempotency_key) where idempotency_key is not null do nothing
+  returning id
+)
+select n.id::text
+from input i
+left join inserted n on n.id = i.id
+order by i.ord;`
+
+// separate statement sees events committed by concurrent transactions while insert waited on conflict
+const findEventIDsByIdempotencyKeysQuery = "select idempotency_key, id::text from events where idempotency_key = any($1);"
+
+// CreateEvents inserts all events with one query, ids are returned in input order.
+// If some events already exist by idempotency key, all ids and ErrAlreadyExists are returned
+func (p *postgres) CreateEvents(ctx context.Context, in []*outbox.CreateEvent) ([]string, error) {
+	const op = "adapter.db.postgres.CreateEvents"
+
+	if len(in) == 0 {
+		return nil, nil
+	}
+
+	tx := p.ex.ExtractTx(ctx)
+
+	query := createEventsQuery
+
+	for _, ev := range in {
+		if ev.GetIdempotencyKey() != "" {
+			query = createEventsIdempotentQuery
+			break
+		}
+	}
+
+	rows, err := tx.Query(ctx, query, createEventsArgs(in)...)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+	}
+
+	ids := make([]string, 0, len(in))
+
+	var created string
+	var missingKeys []string
+
+	for rows.Next() {
+		var id *string
+
+		err = rows.Scan(&id)
+
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+		}
+
+		if id == nil {
+			missingKeys = append(missingKeys, in[len(ids)].GetIdempotencyKey())
+			ids = append(ids, "")
+			continue
+		}
+
+		if created == "" {
+			created = *id
+		}
+
+		ids = append(ids, *id)
+	}
+
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+	}
+
+	if len(ids) != len(in) {
+		return nil, fmt.Errorf("%s: %w: inserted %d of %d events", op, adapters.ErrInternal, len(ids), len(in))
+	}
+
+	if len(missingKeys) > 0 {
+		err = p.fillExistingEventIDs(ctx, in, ids, missingKeys)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if p.notifyChannel != "" && created != "" {
+		_, err = tx.Exec(ctx, notifyEventCreatedQuery, p.notifyChannel, created)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+		}
+	}
+
+	if len(missingKeys) > 0 {
+		return ids, fmt.Errorf("%s: %w", op, kafkalib.ErrAlreadyExists)
+	}
+
+	return ids, nil
+}
+
+// fillExistingEventIDs sets ids of events skipped by idempotency key conflict
+func (p *postgres) fillExistingEventIDs(ctx context.Context, in []*outbox.CreateEvent, ids []string, keys []string) error {
+	const op = "adapter.db.postgres.fillExistingEventIDs"
+
+	tx := p.ex.ExtractTx(ctx)
+
+	rows, err := tx.Query(ctx, findEventIDsByIdempotencyKeysQuery, keys)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+	}
+
+	defer rows.Close()
+
+	existing := make(map[string]string, len(keys))
+
+	for rows.Next() {
+		var key, id string
+
+		err = rows.Scan(&key, &id)
+
+		if err != nil {
+			return fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+		}
+
+		existing[key] = id
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+	}
+
+	for i, id := range ids {
+		if id != "" {
+			continue
+		}
+
+		existingID, ok := existing[in[i].GetIdempotencyKey()]
+
+		if !ok {
+			return fmt.Errorf("%s: %w: event with idempotency key %q not found", op, adapters.ErrInternal, in[i].GetIdempotencyKey())
+		}
+
+		ids[i] = existingID
+	}
+
+	return nil
+}
+
+// createEventsArgs returns one array per column, optional fields are passed as null elements
+func createEventsArgs(in []*outbox.CreateEvent) []any {
+
+	n := len(in)
+
+	aggregateIDs := make([]string, n)
+	topics := make([]string, n)
+	types := make([]string, n)
+	payloads := make([]string, n)
+	createdAt := make([]time.Time, n)
+	traceContexts := make([]*string, n)
+	headers := make([]*string, n)
+	partitionKeys := make([]*string, n)
+	partitions := make([]*int32, n)
+	deliverAfter := make([]*time.Time, n)
+	expiresAt := make([]*time.Time, n)
+	idempotencyKeys := make([]*string, n)
+
+	for i, ev := range in {
+		aggregateIDs[i] = ev.GetAggregateID()
+		topics[i] = ev.GetTopic()
+		types[i] = ev.GetType()
+		payloads[i] = string(ev.GetPayload())
+		createdAt[i] = ev.GetCreatedAt()
+		partitions[i] = ev.GetPartition()
+
+		if len(ev.GetTraceContext()) > 0 {
+			traceContexts[i] = jsonString(ev.GetTraceContext())
+		}
+
+		if len(ev.GetHeaders()) > 0 {
+			headers[i] = jsonString(ev.GetHeaders())
+		}
+
+		if ev.GetPartitionKey() != "" {
+			key := ev.GetPartitionKey()
+			partitionKeys[i] = &key
+		}
+
+		if !ev.GetDeliverAfter().IsZero() {
+			t := ev.GetDeliverAfter().UTC()
+			deliverAfter[i] = &t
+		}
+
+		if !ev.GetExpiresAt().IsZero() {
+			t := ev.GetExpiresAt().UTC()
+			expiresAt[i] = &t
+		}
+
+		if ev.GetIdempotencyKey() != "" {
+			key := ev.GetIdempotencyKey()
+			idempotencyKeys[i] = &key
+		}
+	}
+
+	return []any{aggregateIDs, topics, types, payloads, createdAt,
+		traceContexts, headers, partitionKeys, partitions, deliverAfter, expiresAt, idempotencyKeys}
+}
+
+func jsonString(m map[string]string) *string {
+	// map[string]string is always marshalled without error
+	b, _ := json.Marshal(m)
+	s := string(b)
+	return &s
+}
@@ -0,0 +1,30 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fedotovmax/kafka-lib/adapters"
+)
+
+const incrementEventAttemptsQuery = `update events set attempts = attempts + 1, last_error = $1
+where id = $2 returning attempts;`
+
+func (p *postgres) IncrementEventAttempts(ctx context.Context, id string, lastError string) (int, error) {
+
+	const op = "adapter.db.postgres.IncrementEventAttempts"
+
+	tx := p.ex.ExtractTx(ctx)
+
+	row := tx.QueryRow(ctx, incrementEventAttemptsQuery, lastError, id)
+
+	var attempts int
+
+	err := row.Scan(&attempts)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+	}
+
+	return attempts, nil
+}
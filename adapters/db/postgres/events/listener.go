@@ -0,0 +1,89 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type listener struct {
+	connString string
+	channel    string
+	reconnect  time.Duration
+	log        *slog.Logger
+}
+
+// NewListener creates outbox.Waker, which LISTEN channel on dedicated connection
+// and wakes outbox on each notification. Connection is restored after reconnect delay
+func NewListener(connString string, channel string, reconnect time.Duration, log *slog.Logger) *listener {
+	return &listener{
+		connString: connString,
+		channel:    channel,
+		reconnect:  reconnect,
+		log:        log,
+	}
+}
+
+func (l *listener) Wake(ctx context.Context) <-chan struct{} {
+
+	const op = "adapter.db.postgres.listener.Wake"
+
+	log := l.log.With(slog.String("op", op), slog.String("channel", l.channel))
+
+	wake := make(chan struct{}, 1)
+
+	go func() {
+		defer close(wake)
+		for {
+			err := l.listen(ctx, wake)
+
+			if ctx.Err() != nil {
+				log.Info("context done, exit listening")
+				return
+			}
+
+			log.Error("listen error, reconnecting", slog.String("error", err.Error()))
+
+			select {
+			case <-ctx.Done():
+				log.Info("context done, exit listening")
+				return
+			case <-time.After(l.reconnect):
+			}
+		}
+	}()
+
+	return wake
+}
+
+func (l *listener) listen(ctx context.Context, wake chan<- struct{}) error {
+
+	conn, err := pgx.Connect(ctx, l.connString)
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, "listen "+pgx.Identifier{l.channel}.Sanitize())
+
+	if err != nil {
+		return err
+	}
+
+	for {
+		_, err := conn.WaitForNotification(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
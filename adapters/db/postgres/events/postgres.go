@@ -6,14 +6,33 @@ import (
 	"github.com/fedotovmax/pgxtx"
 )
 
+const DefaultNotifyChannel = "outbox_events"
+
 type postgres struct {
-	ex  pgxtx.Extractor
-	log *slog.Logger
+	ex            pgxtx.Extractor
+	log           *slog.Logger
+	notifyChannel string
+}
+
+type Option func(*postgres)
+
+// WithNotify enables NOTIFY on channel after each CreateEvent.
+// Notification is delivered to listeners when transaction is committed
+func WithNotify(channel string) Option {
+	return func(p *postgres) {
+		p.notifyChannel = channel
+	}
 }
 
-func New(ex pgxtx.Extractor, log *slog.Logger) *postgres {
-	return &postgres{
+func New(ex pgxtx.Extractor, log *slog.Logger, opts ...Option) *postgres {
+	p := &postgres{
 		ex:  ex,
 		log: log,
 	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
 }
@@ -4,22 +4,29 @@ import (
 	"context"
 	"fmt"
 
+	kafkalib "github.com/fedotovmax/kafka-lib"
 	"github.com/fedotovmax/kafka-lib/adapters"
 )
 
-const removeEventReserveQuery = "update events set reserved_to = null where id = $1;"
+const removeEventReserveQuery = `update events set reserved_to = null, reserved_by = null
+where id = $1 and reserved_by = $2;`
 
-func (p *postgres) RemoveEventReserve(ctx context.Context, id string) error {
+// RemoveEventReserve returns ErrMissUpdate, if event is reserved by another owner
+func (p *postgres) RemoveEventReserve(ctx context.Context, id string, reservedBy string) error {
 
 	const op = "adapter.db.postgres.RemoveEventReserve"
 
 	tx := p.ex.ExtractTx(ctx)
 
-	_, err := tx.Exec(ctx, removeEventReserveQuery, id)
+	tag, err := tx.Exec(ctx, removeEventReserveQuery, id, reservedBy)
 
 	if err != nil {
 		return fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
 	}
 
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, kafkalib.ErrMissUpdate)
+	}
+
 	return nil
 }
@@ -0,0 +1,105 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fedotovmax/kafka-lib/adapters"
+	"github.com/fedotovmax/kafka-lib/outbox"
+)
+
+const reserveNewEventsQuery = `with reserved as (
+	update events set reserved_to = $4, reserved_by = gen_random_uuid()
+	where id in (
+		select id from events where status = $1 AND
+		(reserved_to IS NULL OR reserved_to < $2) AND
+		(next_attempt_at IS NULL OR next_attempt_at <= $2) AND
+		(deliver_after IS NULL OR deliver_after <= $2) AND
+		(expires_at IS NULL OR expires_at > $2)
+		order by created_at asc
+		limit $3
+		for update skip locked
+	)
+	returning id, aggregate_id, event_topic, event_type,
+	payload, status, created_at, reserved_to, reserved_by, attempts, last_error, next_attempt_at,
+	trace_context, headers, partition_key, event_partition, deliver_after, expires_at
+)
+select * from reserved order by created_at asc;`
+
+// Event is available only if there are no older unsent (new or failed) events with the same aggregate_id,
+// delayed events do not block their aggregate until deliver_after, expired events never block it
+const reserveNewOrderedEventsQuery = `with reserved as (
+	update events set reserved_to = $4, reserved_by = gen_random_uuid()
+	where id in (
+		select id from events e where status = $1 AND
+		(reserved_to IS NULL OR reserved_to < $2) AND
+		(next_attempt_at IS NULL OR next_attempt_at <= $2) AND
+		(deliver_after IS NULL OR deliver_after <= $2) AND
+		(expires_at IS NULL OR expires_at > $2) AND
+		NOT EXISTS (
+			select 1 from events prev
+			where prev.aggregate_id = e.aggregate_id AND
+			prev.status IN ($1, $5) AND
+			(prev.deliver_after IS NULL OR prev.deliver_after <= $2) AND
+			(prev.expires_at IS NULL OR prev.expires_at > $2) AND
+			(prev.created_at, prev.id) < (e.created_at, e.id)
+		)
+		order by created_at asc
+		limit $3
+		for update skip locked
+	)
+	returning id, aggregate_id, event_topic, event_type,
+	payload, status, created_at, reserved_to, reserved_by, attempts, last_error, next_attempt_at,
+	trace_context, headers, partition_key, event_partition, deliver_after, expires_at
+)
+select * from reserved order by created_at asc;`
+
+func (p *postgres) ReserveNewEvents(ctx context.Context, limit int, dur time.Duration, ordered bool) ([]*outbox.EventModel, error) {
+
+	const op = "adapter.db.postgres.ReserveNewEvents"
+
+	tx := p.ex.ExtractTx(ctx)
+
+	now := time.Now().UTC()
+
+	query := reserveNewEventsQuery
+	args := []any{outbox.EventStatusNew, now, limit, now.Add(dur)}
+
+	if ordered {
+		query = reserveNewOrderedEventsQuery
+		args = append(args, outbox.EventStatusFailed)
+	}
+
+	rows, err := tx.Query(ctx, query, args...)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	var events []*outbox.EventModel
+
+	for rows.Next() {
+
+		e := &outbox.EventModel{}
+
+		err := rows.Scan(&e.ID, &e.AggregateID, &e.Topic, &e.Type, &e.Payload,
+			&e.Status, &e.CreatedAt, &e.ReservedTo, &e.ReservedBy, &e.Attempts, &e.LastError, &e.NextAttemptAt,
+			&e.TraceContext, &e.Headers, &e.PartitionKey, &e.Partition,
+			&e.DeliverAfter, &e.ExpiresAt)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+		}
+
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+	}
+
+	return events, nil
+
+}
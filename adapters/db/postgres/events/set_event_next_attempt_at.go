@@ -0,0 +1,26 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fedotovmax/kafka-lib/adapters"
+)
+
+const setEventNextAttemptAtQuery = "update events set next_attempt_at = $1 where id = $2;"
+
+func (p *postgres) SetEventNextAttemptAt(ctx context.Context, id string, nextAttemptAt time.Time) error {
+
+	const op = "adapter.db.postgres.SetEventNextAttemptAt"
+
+	tx := p.ex.ExtractTx(ctx)
+
+	_, err := tx.Exec(ctx, setEventNextAttemptAtQuery, nextAttemptAt, id)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+	}
+
+	return nil
+}
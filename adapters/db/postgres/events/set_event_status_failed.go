@@ -0,0 +1,25 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fedotovmax/kafka-lib/adapters"
+	"github.com/fedotovmax/kafka-lib/outbox"
+)
+
+const setEventStatusFailedQuery = "update events set status = $1 where id = $2;"
+
+func (p *postgres) SetEventStatusFailed(ctx context.Context, id string) error {
+	const op = "adapter.db.postgres.SetEventStatusFailed"
+
+	tx := p.ex.ExtractTx(ctx)
+
+	_, err := tx.Exec(ctx, setEventStatusFailedQuery, outbox.EventStatusFailed, id)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+	}
+
+	return nil
+}
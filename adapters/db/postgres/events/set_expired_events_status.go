@@ -0,0 +1,56 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fedotovmax/kafka-lib/adapters"
+	"github.com/fedotovmax/kafka-lib/outbox"
+)
+
+const setExpiredEventsStatusQuery = `update events set status = $1, reserved_to = null, reserved_by = null
+where id in (
+	select id from events where status = $2 AND
+	expires_at <= $3 AND
+	(reserved_to IS NULL OR reserved_to < $3)
+	limit $4
+	for update skip locked
+)
+returning id;`
+
+func (p *postgres) SetExpiredEventsStatus(ctx context.Context, limit int) ([]string, error) {
+
+	const op = "adapter.db.postgres.SetExpiredEventsStatus"
+
+	tx := p.ex.ExtractTx(ctx)
+
+	rows, err := tx.Query(ctx, setExpiredEventsStatusQuery,
+		outbox.EventStatusExpired, outbox.EventStatusNew, time.Now().UTC(), limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	var ids []string
+
+	for rows.Next() {
+
+		var id string
+
+		err := rows.Scan(&id)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", op, adapters.ErrInternal, err)
+	}
+
+	return ids, nil
+}
@@ -7,3 +7,7 @@ var ErrInternal = errors.New("internal error")
 var ErrNoNewEvents = errors.New("err no new events")
 
 var ErrMissUpdate = errors.New("unable to update all events")
+
+var ErrCancelTooLate = errors.New("event is already sent or reserved for sending")
+
+var ErrAlreadyExists = errors.New("event with same idempotency key already exists")
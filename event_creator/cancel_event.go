@@ -0,0 +1,30 @@
+package eventcreator
+
+import (
+	"context"
+	"fmt"
+)
+
+func (u *creator) CancelEvent(ctx context.Context, id string) error {
+	const op = "event_creator.CancelEvent"
+
+	err := u.storage.CancelEvent(ctx, id)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (u *creator) CancelAggregateEvents(ctx context.Context, aggregateID string) (int, error) {
+	const op = "event_creator.CancelAggregateEvents"
+
+	cancelled, err := u.storage.CancelAggregateEvents(ctx, aggregateID)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return cancelled, nil
+}
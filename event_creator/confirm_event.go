@@ -13,7 +13,7 @@ func (u *creator) ConfirmEvent(ctx context.Context, ev outbox.SuccessEvent) erro
 
 	err := u.txm.Wrap(ctx, func(txCtx context.Context) error {
 
-		err := u.storage.RemoveEventReserve(txCtx, ev.GetID())
+		err := u.storage.RemoveEventReserve(txCtx, ev.GetID(), ev.GetReservedBy())
 
 		if err != nil {
 			return err
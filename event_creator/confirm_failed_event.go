@@ -3,16 +3,34 @@ package eventcreator
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/fedotovmax/kafka-lib/outbox"
 )
 
-func (u *creator) ConfirmFailedEvent(ctx context.Context, ev outbox.FailedEvent) error {
+func (u *creator) ConfirmFailedEvent(ctx context.Context, ev outbox.FailedEvent, maxAttempts int, backoff outbox.BackoffConfig) error {
 	const op = "event_creator.ConfirmFailedEvent"
 
 	err := u.txm.Wrap(ctx, func(txCtx context.Context) error {
 
-		err := u.storage.RemoveEventReserve(txCtx, ev.GetID())
+		err := u.storage.RemoveEventReserve(txCtx, ev.GetID(), ev.GetReservedBy())
+
+		if err != nil {
+			return err
+		}
+
+		attempts, err := u.storage.IncrementEventAttempts(txCtx, ev.GetID(), ev.GetError().Error())
+
+		if err != nil {
+			return err
+		}
+
+		if attempts < maxAttempts {
+			nextAttemptAt := time.Now().Add(backoff.Delay(attempts)).UTC()
+			return u.storage.SetEventNextAttemptAt(txCtx, ev.GetID(), nextAttemptAt)
+		}
+
+		err = u.storage.SetEventStatusFailed(txCtx, ev.GetID())
 
 		if err != nil {
 			return err
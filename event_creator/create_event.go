@@ -2,15 +2,21 @@ package eventcreator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	kafkalib "github.com/fedotovmax/kafka-lib"
 	"github.com/fedotovmax/kafka-lib/outbox"
 )
 
 func (u *creator) CreateEvent(ctx context.Context, d *outbox.CreateEvent) (string, error) {
-	const op = "event_creator.ConfirmFailedEvent"
+	const op = "event_creator.CreateEvent"
 
-	res, err := u.storage.CreateEvent(ctx, d)
+	res, err := u.storage.CreateEvent(ctx, withTraceContext(ctx, d))
+
+	if errors.Is(err, kafkalib.ErrAlreadyExists) {
+		return res, fmt.Errorf("%s: %w", op, err)
+	}
 
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
@@ -0,0 +1,32 @@
+package eventcreator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	kafkalib "github.com/fedotovmax/kafka-lib"
+	"github.com/fedotovmax/kafka-lib/outbox"
+)
+
+func (u *creator) CreateEvents(ctx context.Context, d []*outbox.CreateEvent) ([]string, error) {
+	const op = "event_creator.CreateEvents"
+
+	events := make([]*outbox.CreateEvent, len(d))
+
+	for i, ev := range d {
+		events[i] = withTraceContext(ctx, ev)
+	}
+
+	res, err := u.storage.CreateEvents(ctx, events)
+
+	if errors.Is(err, kafkalib.ErrAlreadyExists) {
+		return res, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
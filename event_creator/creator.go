@@ -10,10 +10,16 @@ import (
 
 type Storage interface {
 	SetEventStatusDone(ctx context.Context, id string) error
-	SetEventsReservedToByIDs(ctx context.Context, ids []string, dur time.Duration) error
-	RemoveEventReserve(ctx context.Context, id string) error
+	SetEventStatusFailed(ctx context.Context, id string) error
+	IncrementEventAttempts(ctx context.Context, id string, lastError string) (int, error)
+	SetEventNextAttemptAt(ctx context.Context, id string, nextAttemptAt time.Time) error
+	RemoveEventReserve(ctx context.Context, id string, reservedBy string) error
 	CreateEvent(ctx context.Context, d *outbox.CreateEvent) (string, error)
-	FindNewAndNotReservedEvents(ctx context.Context, limit int) ([]*outbox.EventModel, error)
+	CreateEvents(ctx context.Context, d []*outbox.CreateEvent) ([]string, error)
+	CancelEvent(ctx context.Context, id string) error
+	CancelAggregateEvents(ctx context.Context, aggregateID string) (int, error)
+	ReserveNewEvents(ctx context.Context, limit int, dur time.Duration, ordered bool) ([]*outbox.EventModel, error)
+	SetExpiredEventsStatus(ctx context.Context, limit int) ([]string, error)
 }
 
 type creator struct {
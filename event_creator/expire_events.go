@@ -0,0 +1,19 @@
+package eventcreator
+
+import (
+	"context"
+	"fmt"
+)
+
+func (u *creator) ExpireEvents(ctx context.Context, limit int) ([]string, error) {
+
+	const op = "event_creator.ExpireEvents"
+
+	ids, err := u.storage.SetExpiredEventsStatus(ctx, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ids, nil
+}
@@ -9,34 +9,11 @@ import (
 	"github.com/fedotovmax/kafka-lib/outbox"
 )
 
-func (u *creator) ReserveNewEvents(ctx context.Context, limit int, reserveDuration time.Duration) ([]outbox.Event, error) {
+func (u *creator) ReserveNewEvents(ctx context.Context, limit int, reserveDuration time.Duration, ordered bool) ([]outbox.Event, error) {
 
 	const op = "event_creator.ReserveNewEvents"
 
-	var events []*outbox.EventModel
-
-	err := u.txm.Wrap(ctx, func(txCtx context.Context) error {
-		var err error
-		events, err = u.storage.FindNewAndNotReservedEvents(txCtx, limit)
-
-		if err != nil {
-			return err
-		}
-
-		eventsIds := make([]string, len(events))
-
-		for i := 0; i < len(events); i++ {
-			eventsIds[i] = events[i].ID
-		}
-
-		err = u.storage.SetEventsReservedToByIDs(txCtx, eventsIds, reserveDuration)
-
-		if err != nil {
-			return err
-		}
-
-		return nil
-	})
+	events, err := u.storage.ReserveNewEvents(ctx, limit, reserveDuration, ordered)
 
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -0,0 +1,25 @@
+package eventcreator
+
+import (
+	"context"
+
+	"github.com/fedotovmax/kafka-lib/outbox"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// withTraceContext returns copy of event with W3C trace context from ctx,
+// so input of caller can be reused without previous trace
+func withTraceContext(ctx context.Context, d *outbox.CreateEvent) *outbox.CreateEvent {
+	traceContext := propagation.MapCarrier{}
+
+	otel.GetTextMapPropagator().Inject(ctx, traceContext)
+
+	ev := *d
+
+	if len(traceContext) > 0 {
+		ev.SetTraceContext(traceContext)
+	}
+
+	return &ev
+}
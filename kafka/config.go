@@ -1,11 +1,77 @@
 package kafka
 
-import "time"
+import (
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+type Acks string
+
+const (
+	AcksAll    Acks = "all"
+	AcksLeader Acks = "leader"
+	AcksNone   Acks = "none"
+)
+
+type Compression string
+
+const (
+	CompressionNone   Compression = "none"
+	CompressionGZIP   Compression = "gzip"
+	CompressionSnappy Compression = "snappy"
+	CompressionLZ4    Compression = "lz4"
+	CompressionZSTD   Compression = "zstd"
+)
+
+type Partitioner string
+
+const (
+	PartitionerHash       Partitioner = "hash"
+	PartitionerRandom     Partitioner = "random"
+	PartitionerRoundRobin Partitioner = "roundrobin"
+	PartitionerManual     Partitioner = "manual"
+)
 
 type ProducerConfig struct {
 	Brokers     []string
 	MaxMessages int
 	Frequency   time.Duration
+	// Enables idempotent transactional producer, must be unique for each producer instance
+	TransactionalID string
+
+	// Kafka version, default = 4.1.0
+	Version string
+	// Client id for broker quotas and logs, default = sarama
+	ClientID string
+	// Required acks: all, leader, none, default = all (transactional producer requires all)
+	Acks Acks
+	// Max retries of message, nil = 5, 0 = retries disabled (not allowed for transactional producer)
+	RetryMax *int
+	// Delay between retries, default = 100ms
+	RetryBackoff time.Duration
+	// Compression codec: none, gzip, snappy, lz4, zstd, default = none
+	Compression Compression
+	// Compression level, default = codec default
+	CompressionLevel int
+	// Partitioner: hash, random, roundrobin, manual, default = hash.
+	// Messages with ExplicitPartitionMetadata are always sent to its partition
+	Partitioner Partitioner
+	// Max message size in bytes, default = 1000000
+	MaxMessageBytes int
+	// Max time to wait for required acks, default = 10s
+	Timeout time.Duration
+	// Network timeouts, default = 30s
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// TLS and SASL settings, nil = plaintext connection
+	Security *SecurityConfig
+
+	// Configure is called with final sarama config before producer construction.
+	// Partitioner set in Configure is wrapped to respect ExplicitPartitionMetadata
+	Configure func(cfg *sarama.Config)
 }
 
 type ConsumerGroupConfig struct {
@@ -14,4 +80,8 @@ type ConsumerGroupConfig struct {
 	SleepAfterRebalance time.Duration
 	GroupID             string
 	AutoCommit          bool
+	// Read only committed messages of transactional producers
+	ReadCommitted bool
+	// TLS and SASL settings, nil = plaintext connection
+	Security *SecurityConfig
 }
@@ -24,6 +24,12 @@ type consumerGroup struct {
 	isStopped chan struct{}
 }
 
+// autoCommitAware is implemented by handlers of this package, which commit marked offsets themselves,
+// if auto commit is disabled
+type autoCommitAware interface {
+	setAutoCommit(enabled bool)
+}
+
 type ConsumerGroup interface {
 	Stop(context.Context) error
 	Start()
@@ -36,15 +42,28 @@ func NewConsumerGroup(cgcfg *ConsumerGroupConfig, log *slog.Logger, handler sara
 	cfg.Version = sarama.V4_1_0_0
 	cfg.Consumer.Offsets.Initial = sarama.OffsetOldest
 	cfg.Consumer.IsolationLevel = sarama.ReadUncommitted
+	if cgcfg.ReadCommitted {
+		cfg.Consumer.IsolationLevel = sarama.ReadCommitted
+	}
 	cfg.Consumer.Return.Errors = true
 	cfg.Consumer.Offsets.AutoCommit.Enable = cgcfg.AutoCommit
 
+	err := applySecurity(cfg, cgcfg.Security)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: security: %w", op, err)
+	}
+
 	cg, err := sarama.NewConsumerGroup(cgcfg.Brokers, cgcfg.GroupID, cfg)
 
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	if h, ok := handler.(autoCommitAware); ok {
+		h.setAutoCommit(cgcfg.AutoCommit)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &consumerGroup{
@@ -59,6 +78,11 @@ func NewConsumerGroup(cgcfg *ConsumerGroupConfig, log *slog.Logger, handler sara
 	}, nil
 }
 
+// NewMessageConsumerGroup creates consumer group for MessageHandler wrapped with middlewares
+func NewMessageConsumerGroup(cgcfg *ConsumerGroupConfig, log *slog.Logger, handler MessageHandler, mws ...Middleware) (ConsumerGroup, error) {
+	return NewConsumerGroup(cgcfg, log, NewClaimHandler(Chain(handler, mws...)))
+}
+
 func (cg *consumerGroup) readErrors(wg *sync.WaitGroup) {
 
 	const op = "queues.kafka.consumer-group.readErrors"
@@ -0,0 +1,115 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/IBM/sarama"
+)
+
+const DefaultDLQSuffix = ".dlq"
+
+type deadLetter struct {
+	producer sarama.SyncProducer
+	log      *slog.Logger
+	topic    func(topic string) string
+}
+
+type DLQOption func(*deadLetter)
+
+// WithDLQTopic sets dead-letter topic name for original topic, default = <topic>.dlq
+func WithDLQTopic(fn func(topic string) string) DLQOption {
+	return func(d *deadLetter) {
+		d.topic = fn
+	}
+}
+
+// DeadLetter republishes message, which handler failed to process, to dead-letter topic
+// with original topic, partition, offset, error text and attempts count in headers,
+// then returns nil, so original message is marked.
+// Should be placed outside Retry. If republishing fails or session is done, handler error is returned
+func DeadLetter(p sarama.SyncProducer, log *slog.Logger, opts ...DLQOption) Middleware {
+	d := &deadLetter{
+		producer: p,
+		log:      log,
+		topic: func(topic string) string {
+			return topic + DefaultDLQSuffix
+		},
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return func(next MessageHandler) MessageHandler {
+		return MessageHandlerFunc(func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+			err := next.Handle(ctx, msg)
+
+			if err == nil || ctx.Err() != nil {
+				return err
+			}
+
+			return d.send(msg, err)
+		})
+	}
+}
+
+func (d *deadLetter) send(msg *sarama.ConsumerMessage, handleErr error) error {
+	const op = "queues.kafka.dlq.send"
+
+	attempts := 1
+
+	var retryErr *RetryError
+
+	if errors.As(handleErr, &retryErr) {
+		attempts = retryErr.Attempts
+	}
+
+	headers := make([]sarama.RecordHeader, 0, len(msg.Headers)+5)
+
+	for _, h := range msg.Headers {
+		if h != nil && !strings.HasPrefix(string(h.Key), "dlq_") {
+			headers = append(headers, *h)
+		}
+	}
+
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(HeaderDLQOriginalTopic), Value: []byte(msg.Topic)},
+		sarama.RecordHeader{Key: []byte(HeaderDLQOriginalPartition), Value: []byte(strconv.Itoa(int(msg.Partition)))},
+		sarama.RecordHeader{Key: []byte(HeaderDLQOriginalOffset), Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		sarama.RecordHeader{Key: []byte(HeaderDLQError), Value: []byte(handleErr.Error())},
+		sarama.RecordHeader{Key: []byte(HeaderDLQAttempts), Value: []byte(strconv.Itoa(attempts))},
+	)
+
+	dlqMsg := &sarama.ProducerMessage{
+		Topic:   d.topic(msg.Topic),
+		Value:   sarama.ByteEncoder(msg.Value),
+		Headers: headers,
+	}
+
+	if msg.Key != nil {
+		dlqMsg.Key = sarama.ByteEncoder(msg.Key)
+	}
+
+	_, _, err := d.producer.SendMessage(dlqMsg)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w: handler error: %v", op, err, handleErr)
+	}
+
+	d.log.Warn("message sent to dead-letter topic",
+		slog.String("op", op),
+		slog.String("dlq_topic", dlqMsg.Topic),
+		slog.String("topic", msg.Topic),
+		slog.Int("partition", int(msg.Partition)),
+		slog.Int64("offset", msg.Offset),
+		slog.Int("attempts", attempts),
+		slog.String("error", handleErr.Error()),
+	)
+
+	return nil
+}
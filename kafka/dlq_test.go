@@ -0,0 +1,160 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+)
+
+func TestDeadLetter(t *testing.T) {
+	errSend := errors.New("send error")
+
+	tests := []struct {
+		name         string
+		handlerErr   error
+		opts         []DLQOption
+		sendErr      error
+		wantSend     bool
+		wantTopic    string
+		wantAttempts string
+		wantErr      bool
+	}{
+		{name: "success is not sent", handlerErr: nil},
+		{name: "failed message is sent", handlerErr: errTest, wantSend: true, wantTopic: "orders.dlq", wantAttempts: "1"},
+		{
+			name:         "attempts from retry error",
+			handlerErr:   &RetryError{Attempts: 3, Err: errTest},
+			wantSend:     true,
+			wantTopic:    "orders.dlq",
+			wantAttempts: "3",
+		},
+		{
+			name:         "custom topic",
+			handlerErr:   errTest,
+			opts:         []DLQOption{WithDLQTopic(func(topic string) string { return "dead-letters" })},
+			wantSend:     true,
+			wantTopic:    "dead-letters",
+			wantAttempts: "1",
+		},
+		{name: "send error is returned", handlerErr: errTest, sendErr: errSend, wantSend: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := mocks.NewSyncProducer(t, nil)
+			defer p.Close()
+
+			var sent *sarama.ProducerMessage
+
+			if tt.wantSend {
+				checker := func(msg *sarama.ProducerMessage) error {
+					sent = msg
+					return nil
+				}
+				if tt.sendErr != nil {
+					p.ExpectSendMessageWithMessageCheckerFunctionAndFail(checker, tt.sendErr)
+				} else {
+					p.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(checker)
+				}
+			}
+
+			h := Chain(MessageHandlerFunc(func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+				return tt.handlerErr
+			}), DeadLetter(p, discardLogger(), tt.opts...))
+
+			msg := newTestMessage("order.created", `{"id":"order-1"}`)
+			msg.Headers = append(msg.Headers, &sarama.RecordHeader{Key: []byte(HeaderDLQError), Value: []byte("previous error")})
+
+			err := h.Handle(context.Background(), msg)
+
+			if tt.wantErr {
+				if !errors.Is(err, errSend) {
+					t.Fatalf("error = %v, want send error", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !tt.wantSend {
+				return
+			}
+
+			if sent.Topic != tt.wantTopic {
+				t.Errorf("topic = %q, want %q", sent.Topic, tt.wantTopic)
+			}
+
+			headers := make(map[string][]string)
+
+			for _, h := range sent.Headers {
+				headers[string(h.Key)] = append(headers[string(h.Key)], string(h.Value))
+			}
+
+			wantHeaders := map[string]string{
+				HeaderEventID:              "event-1",
+				HeaderEventType:            "order.created",
+				"tenant_id":                "tenant-1",
+				HeaderDLQOriginalTopic:     "orders",
+				HeaderDLQOriginalPartition: "1",
+				HeaderDLQOriginalOffset:    "42",
+				HeaderDLQError:             tt.handlerErr.Error(),
+				HeaderDLQAttempts:          tt.wantAttempts,
+			}
+
+			for k, v := range wantHeaders {
+				if len(headers[k]) != 1 || headers[k][0] != v {
+					t.Errorf("header %s = %v, want [%s]", k, headers[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestDeadLetterSkipsDoneSession(t *testing.T) {
+	p := mocks.NewSyncProducer(t, nil)
+	defer p.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	h := Chain(MessageHandlerFunc(func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+		return errTest
+	}), DeadLetter(p, discardLogger()))
+
+	if err := h.Handle(ctx, newTestMessage("order.created", `{}`)); !errors.Is(err, errTest) {
+		t.Fatalf("error = %v, want handler error", err)
+	}
+}
+
+func TestDeadLetterCommitsOffset(t *testing.T) {
+	p := mocks.NewSyncProducer(t, nil)
+	defer p.Close()
+
+	p.ExpectSendMessageAndSucceed()
+
+	h := NewClaimHandler(Chain(MessageHandlerFunc(func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+		return errTest
+	}), DeadLetter(p, discardLogger())))
+
+	h.(autoCommitAware).setAutoCommit(false)
+
+	msg := newTestMessage("order.created", `{"id":"order-1"}`)
+	sess := newFakeSession()
+
+	if err := h.ConsumeClaim(sess, newFakeClaim(msg)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(sess.marked) != 1 || sess.marked[0] != msg {
+		t.Errorf("marked = %v, want dead lettered message", sess.marked)
+	}
+
+	if sess.commits != 1 {
+		t.Errorf("commits = %d, want 1", sess.commits)
+	}
+}
@@ -0,0 +1,53 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+)
+
+// fakeSession records marked messages and commits, other session methods are not used by handlers
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+	ctx     context.Context
+	marked  []*sarama.ConsumerMessage
+	commits int
+}
+
+func newFakeSession() *fakeSession {
+	return &fakeSession{ctx: context.Background()}
+}
+
+func (s *fakeSession) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.marked = append(s.marked, msg)
+}
+
+func (s *fakeSession) Commit() {
+	s.commits++
+}
+
+// fakeClaim serves given messages and closes the channel
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+}
+
+func newFakeClaim(msgs ...*sarama.ConsumerMessage) *fakeClaim {
+	c := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, len(msgs))}
+
+	for _, msg := range msgs {
+		c.messages <- msg
+	}
+
+	close(c.messages)
+
+	return c
+}
+
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
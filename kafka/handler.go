@@ -0,0 +1,76 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM/sarama"
+)
+
+// MessageHandler handles single message, returned error stops the session
+// and message is consumed again after rebalance
+type MessageHandler interface {
+	Handle(ctx context.Context, msg *sarama.ConsumerMessage) error
+}
+
+type MessageHandlerFunc func(ctx context.Context, msg *sarama.ConsumerMessage) error
+
+func (f MessageHandlerFunc) Handle(ctx context.Context, msg *sarama.ConsumerMessage) error {
+	return f(ctx, msg)
+}
+
+type claimHandler struct {
+	handler    MessageHandler
+	autoCommit bool
+}
+
+// NewClaimHandler adapts MessageHandler to sarama.ConsumerGroupHandler.
+// Messages are handled one by one with trace context extracted from headers,
+// message is marked only after successful handling and committed, if auto commit is disabled
+func NewClaimHandler(h MessageHandler) sarama.ConsumerGroupHandler {
+	return &claimHandler{handler: h}
+}
+
+func (c *claimHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (c *claimHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (c *claimHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	return consumeClaim(sess, claim, c.handler, c.autoCommit)
+}
+
+func (c *claimHandler) setAutoCommit(enabled bool) {
+	c.autoCommit = enabled
+}
+
+// consumeClaim commits each marked message, if auto commit is disabled, sarama flushes marked offsets only with auto commit
+func consumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim, h MessageHandler, autoCommit bool) error {
+	const op = "queues.kafka.consumeClaim"
+
+	for {
+		select {
+		case <-sess.Context().Done():
+			return nil
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			err := h.Handle(ContextFromMessage(sess.Context(), msg), msg)
+
+			if err != nil {
+				return fmt.Errorf("%s: topic %s, partition %d, offset %d: %w", op, msg.Topic, msg.Partition, msg.Offset, err)
+			}
+
+			sess.MarkMessage(msg, "")
+
+			if !autoCommit {
+				sess.Commit()
+			}
+		}
+	}
+}
@@ -2,3 +2,10 @@ package kafka
 
 const HeaderEventID = "event_id"
 const HeaderEventType = "event_type"
+
+// Headers of messages republished to dead-letter topic
+const HeaderDLQOriginalTopic = "dlq_original_topic"
+const HeaderDLQOriginalPartition = "dlq_original_partition"
+const HeaderDLQOriginalOffset = "dlq_original_offset"
+const HeaderDLQError = "dlq_error"
+const HeaderDLQAttempts = "dlq_attempts"
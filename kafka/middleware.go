@@ -0,0 +1,183 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+var ErrHandlerPanic = errors.New("message handler panic")
+
+// Middleware wraps MessageHandler with additional behaviour
+type Middleware func(MessageHandler) MessageHandler
+
+// Chain wraps handler with middlewares, first middleware is the outermost.
+// Recommended order: Logging, Retry, Timeout, Recover
+func Chain(h MessageHandler, mws ...Middleware) MessageHandler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// Recover converts handler panic to ErrHandlerPanic, so panic does not kill the consumer
+func Recover(log *slog.Logger) Middleware {
+	const op = "queues.kafka.middleware.Recover"
+
+	return func(next MessageHandler) MessageHandler {
+		return MessageHandlerFunc(func(ctx context.Context, msg *sarama.ConsumerMessage) (err error) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					log.Error("message handler panic",
+						slog.String("op", op),
+						slog.String("topic", msg.Topic),
+						slog.Int("partition", int(msg.Partition)),
+						slog.Int64("offset", msg.Offset),
+						slog.Any("panic", rec),
+						slog.String("stack", string(debug.Stack())),
+					)
+					err = fmt.Errorf("%w: %v", ErrHandlerPanic, rec)
+				}
+			}()
+
+			return next.Handle(ctx, msg)
+		})
+	}
+}
+
+// Logging logs result and duration of each message handling
+func Logging(log *slog.Logger) Middleware {
+	const op = "queues.kafka.middleware.Logging"
+
+	return func(next MessageHandler) MessageHandler {
+		return MessageHandlerFunc(func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+			start := time.Now()
+
+			err := next.Handle(ctx, msg)
+
+			attrs := []slog.Attr{
+				slog.String("op", op),
+				slog.String("topic", msg.Topic),
+				slog.Int("partition", int(msg.Partition)),
+				slog.Int64("offset", msg.Offset),
+				slog.String("event_type", headerValue(msg, HeaderEventType)),
+				slog.String("event_id", headerValue(msg, HeaderEventID)),
+				slog.Duration("duration", time.Since(start)),
+			}
+
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+				log.LogAttrs(ctx, slog.LevelError, "message handling failed", attrs...)
+				return err
+			}
+
+			log.LogAttrs(ctx, slog.LevelDebug, "message handled", attrs...)
+
+			return nil
+		})
+	}
+}
+
+// Timeout limits handling time of one message, handler must respect ctx
+func Timeout(d time.Duration) Middleware {
+	return func(next MessageHandler) MessageHandler {
+		return MessageHandlerFunc(func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			return next.Handle(ctx, msg)
+		})
+	}
+}
+
+const DefaultRetryBackoff = 100 * time.Millisecond
+
+type RetryConfig struct {
+	// Total handling attempts, including the first one, min = 1, values < 1 are treated as 1
+	MaxAttempts int
+	// Delay before second attempt, doubled for each next attempt, default = 100ms
+	Backoff time.Duration
+	// Max delay between attempts, 0 = unlimited, min = Backoff
+	MaxBackoff time.Duration
+}
+
+func (c RetryConfig) normalize() RetryConfig {
+	if c.MaxAttempts < 1 {
+		c.MaxAttempts = 1
+	}
+
+	if c.Backoff <= 0 {
+		c.Backoff = DefaultRetryBackoff
+	}
+
+	if c.MaxBackoff < 0 || (c.MaxBackoff > 0 && c.MaxBackoff < c.Backoff) {
+		c.MaxBackoff = c.Backoff
+	}
+
+	return c
+}
+
+// RetryError is returned by Retry when all attempts failed
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Retry handles message again in place with exponential backoff,
+// retrying is stopped when session context is done
+func Retry(cfg RetryConfig) Middleware {
+	cfg = cfg.normalize()
+
+	return func(next MessageHandler) MessageHandler {
+		return MessageHandlerFunc(func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+			delay := cfg.Backoff
+			attempts := 0
+
+			for {
+				attempts++
+
+				err := next.Handle(ctx, msg)
+
+				if err == nil {
+					return nil
+				}
+
+				if attempts >= cfg.MaxAttempts {
+					return &RetryError{Attempts: attempts, Err: err}
+				}
+
+				timer := time.NewTimer(delay)
+
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return &RetryError{Attempts: attempts, Err: err}
+				case <-timer.C:
+				}
+
+				delay *= 2
+
+				if cfg.MaxBackoff > 0 && delay > cfg.MaxBackoff {
+					delay = cfg.MaxBackoff
+				}
+			}
+		})
+	}
+}
+
+func headerValue(msg *sarama.ConsumerMessage, key string) string {
+	return (&headersCarrier{headers: msg.Headers}).Get(key)
+}
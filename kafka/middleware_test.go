@@ -0,0 +1,209 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+var errTest = errors.New("test error")
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestChainOrder(t *testing.T) {
+	var calls []string
+
+	mw := func(name string) Middleware {
+		return func(next MessageHandler) MessageHandler {
+			return MessageHandlerFunc(func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+				calls = append(calls, name)
+				return next.Handle(ctx, msg)
+			})
+		}
+	}
+
+	h := Chain(MessageHandlerFunc(func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+		calls = append(calls, "handler")
+		return nil
+	}), mw("first"), mw("second"))
+
+	if err := h.Handle(context.Background(), &sarama.ConsumerMessage{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"first", "second", "handler"}
+
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	}
+}
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler MessageHandlerFunc
+		wantErr error
+	}{
+		{
+			name:    "no panic",
+			handler: func(ctx context.Context, msg *sarama.ConsumerMessage) error { return nil },
+		},
+		{
+			name:    "handler error is passed",
+			handler: func(ctx context.Context, msg *sarama.ConsumerMessage) error { return errTest },
+			wantErr: errTest,
+		},
+		{
+			name:    "panic is converted to error",
+			handler: func(ctx context.Context, msg *sarama.ConsumerMessage) error { panic("boom") },
+			wantErr: ErrHandlerPanic,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Chain(tt.handler, Recover(discardLogger()))
+
+			err := h.Handle(context.Background(), &sarama.ConsumerMessage{Topic: "orders"})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryConfigNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  RetryConfig
+		want RetryConfig
+	}{
+		{
+			name: "zero config",
+			cfg:  RetryConfig{},
+			want: RetryConfig{MaxAttempts: 1, Backoff: DefaultRetryBackoff},
+		},
+		{
+			name: "negative values",
+			cfg:  RetryConfig{MaxAttempts: -1, Backoff: -time.Second, MaxBackoff: -time.Second},
+			want: RetryConfig{MaxAttempts: 1, Backoff: DefaultRetryBackoff, MaxBackoff: DefaultRetryBackoff},
+		},
+		{
+			name: "max backoff less than backoff",
+			cfg:  RetryConfig{MaxAttempts: 3, Backoff: time.Second, MaxBackoff: time.Millisecond},
+			want: RetryConfig{MaxAttempts: 3, Backoff: time.Second, MaxBackoff: time.Second},
+		},
+		{
+			name: "valid config is not changed",
+			cfg:  RetryConfig{MaxAttempts: 5, Backoff: time.Millisecond, MaxBackoff: time.Second},
+			want: RetryConfig{MaxAttempts: 5, Backoff: time.Millisecond, MaxBackoff: time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.normalize(); got != tt.want {
+				t.Errorf("normalize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxAttempts  int
+		failures     int
+		wantCalls    int
+		wantAttempts int
+	}{
+		{name: "success on first attempt", maxAttempts: 3, failures: 0, wantCalls: 1},
+		{name: "success after retries", maxAttempts: 3, failures: 2, wantCalls: 3},
+		{name: "all attempts failed", maxAttempts: 3, failures: 5, wantCalls: 3, wantAttempts: 3},
+		{name: "zero attempts handled once", maxAttempts: 0, failures: 5, wantCalls: 1, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+
+			h := Chain(MessageHandlerFunc(func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+				calls++
+				if calls <= tt.failures {
+					return errTest
+				}
+				return nil
+			}), Retry(RetryConfig{MaxAttempts: tt.maxAttempts, Backoff: time.Millisecond}))
+
+			err := h.Handle(context.Background(), &sarama.ConsumerMessage{})
+
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+
+			if tt.wantAttempts == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var retryErr *RetryError
+
+			if !errors.As(err, &retryErr) || !errors.Is(err, errTest) {
+				t.Fatalf("error = %v, want RetryError wrapping test error", err)
+			}
+
+			if retryErr.Attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", retryErr.Attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryStopsOnContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+
+	h := Chain(MessageHandlerFunc(func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+		calls++
+		cancel()
+		return errTest
+	}), Retry(RetryConfig{MaxAttempts: 5, Backoff: time.Hour}))
+
+	err := h.Handle(ctx, &sarama.ConsumerMessage{})
+
+	var retryErr *RetryError
+
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 1 || calls != 1 {
+		t.Fatalf("error = %v, calls = %d, want one attempt", err, calls)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	h := Chain(MessageHandlerFunc(func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), Timeout(time.Millisecond))
+
+	err := h.Handle(context.Background(), &sarama.ConsumerMessage{})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want deadline exceeded", err)
+	}
+}
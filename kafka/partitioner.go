@@ -0,0 +1,57 @@
+package kafka
+
+import "github.com/IBM/sarama"
+
+// ExplicitPartitionMetadata is implemented by ProducerMessage.Metadata, which requires exact partition
+type ExplicitPartitionMetadata interface {
+	ExplicitPartition() (int32, bool)
+}
+
+type explicitPartitioner struct {
+	fallback sarama.Partitioner
+}
+
+// NewExplicitPartitioner sends message to partition from ExplicitPartitionMetadata,
+// other messages are partitioned by fallback
+func NewExplicitPartitioner(fallback sarama.PartitionerConstructor) sarama.PartitionerConstructor {
+	return func(topic string) sarama.Partitioner {
+		return &explicitPartitioner{fallback: fallback(topic)}
+	}
+}
+
+func (p *explicitPartitioner) Partition(msg *sarama.ProducerMessage, numPartitions int32) (int32, error) {
+
+	if partition, ok := explicitPartition(msg); ok {
+		if partition < 0 || partition >= numPartitions {
+			return -1, sarama.ErrInvalidPartition
+		}
+		return partition, nil
+	}
+
+	return p.fallback.Partition(msg, numPartitions)
+}
+
+func (p *explicitPartitioner) RequiresConsistency() bool {
+	return p.fallback.RequiresConsistency()
+}
+
+func (p *explicitPartitioner) MessageRequiresConsistency(msg *sarama.ProducerMessage) bool {
+
+	if _, ok := explicitPartition(msg); ok {
+		return true
+	}
+
+	if dp, ok := p.fallback.(sarama.DynamicConsistencyPartitioner); ok {
+		return dp.MessageRequiresConsistency(msg)
+	}
+
+	return p.fallback.RequiresConsistency()
+}
+
+func explicitPartition(msg *sarama.ProducerMessage) (int32, bool) {
+	m, ok := msg.Metadata.(ExplicitPartitionMetadata)
+	if !ok {
+		return 0, false
+	}
+	return m.ExplicitPartition()
+}
@@ -0,0 +1,76 @@
+package kafka
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+type partitionMetadata struct {
+	partition *int32
+}
+
+func (m partitionMetadata) ExplicitPartition() (int32, bool) {
+	if m.partition == nil {
+		return 0, false
+	}
+	return *m.partition, true
+}
+
+// fixedPartitioner is fallback, which always returns partition 2 and does not require consistency
+type fixedPartitioner struct{}
+
+func (fixedPartitioner) Partition(*sarama.ProducerMessage, int32) (int32, error) {
+	return 2, nil
+}
+
+func (fixedPartitioner) RequiresConsistency() bool {
+	return false
+}
+
+func int32Ptr(v int32) *int32 {
+	return &v
+}
+
+func TestExplicitPartitioner(t *testing.T) {
+	tests := []struct {
+		name        string
+		metadata    any
+		want        int32
+		wantErr     error
+		consistency bool
+	}{
+		{name: "explicit partition", metadata: partitionMetadata{partition: int32Ptr(3)}, want: 3, consistency: true},
+		{name: "explicit first partition", metadata: partitionMetadata{partition: int32Ptr(0)}, want: 0, consistency: true},
+		{name: "partition out of range", metadata: partitionMetadata{partition: int32Ptr(4)}, want: -1, wantErr: sarama.ErrInvalidPartition, consistency: true},
+		{name: "negative partition", metadata: partitionMetadata{partition: int32Ptr(-1)}, want: -1, wantErr: sarama.ErrInvalidPartition, consistency: true},
+		{name: "metadata without partition uses fallback", metadata: partitionMetadata{}, want: 2},
+		{name: "other metadata uses fallback", metadata: "event", want: 2},
+		{name: "no metadata uses fallback", want: 2},
+	}
+
+	p := NewExplicitPartitioner(func(string) sarama.Partitioner { return fixedPartitioner{} })("topic")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &sarama.ProducerMessage{Topic: "topic", Metadata: tt.metadata}
+
+			got, err := p.Partition(msg, 4)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("partition = %d, want %d", got, tt.want)
+			}
+
+			dp := p.(sarama.DynamicConsistencyPartitioner)
+
+			if got := dp.MessageRequiresConsistency(msg); got != tt.consistency {
+				t.Errorf("MessageRequiresConsistency = %v, want %v", got, tt.consistency)
+			}
+		})
+	}
+}
@@ -11,6 +11,11 @@ type Producer interface {
 	GetInput() chan<- *sarama.ProducerMessage
 	GetSuccesses() <-chan *sarama.ProducerMessage
 	GetErrors() <-chan *sarama.ProducerError
+	IsTransactional() bool
+	BeginTxn() error
+	CommitTxn() error
+	AbortTxn() error
+	TxnStatus() sarama.ProducerTxnStatusFlag
 	Stop(context.Context) error
 }
 
@@ -30,6 +35,26 @@ func (p *producer) GetErrors() <-chan *sarama.ProducerError {
 	return p.instance.Errors()
 }
 
+func (p *producer) IsTransactional() bool {
+	return p.instance.IsTransactional()
+}
+
+func (p *producer) BeginTxn() error {
+	return p.instance.BeginTxn()
+}
+
+func (p *producer) CommitTxn() error {
+	return p.instance.CommitTxn()
+}
+
+func (p *producer) AbortTxn() error {
+	return p.instance.AbortTxn()
+}
+
+func (p *producer) TxnStatus() sarama.ProducerTxnStatusFlag {
+	return p.instance.TxnStatus()
+}
+
 func (p *producer) Stop(ctx context.Context) error {
 
 	const op = "queues.kafka.producer.Close"
@@ -55,13 +80,11 @@ func (p *producer) Stop(ctx context.Context) error {
 func NewAsyncProducer(pcfg ProducerConfig) (Producer, error) {
 	const op = "queues.kafka.producer.NewAsyncProducer"
 
-	cfg := sarama.NewConfig()
-	cfg.Version = sarama.V4_1_0_0
-	cfg.Producer.Return.Successes = true
-	cfg.Producer.Retry.Max = 5
-	cfg.Producer.RequiredAcks = sarama.WaitForAll
-	cfg.Producer.Flush.MaxMessages = pcfg.MaxMessages
-	cfg.Producer.Flush.Frequency = pcfg.Frequency
+	cfg, err := newProducerSaramaConfig(pcfg)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
 	p, err := sarama.NewAsyncProducer(pcfg.Brokers, cfg)
 	if err != nil {
@@ -72,3 +95,26 @@ func NewAsyncProducer(pcfg ProducerConfig) (Producer, error) {
 		instance: p,
 	}, nil
 }
+
+// NewSyncProducer creates producer for single messages, for example for dead-letter topic.
+// TransactionalID is not supported
+func NewSyncProducer(pcfg ProducerConfig) (sarama.SyncProducer, error) {
+	const op = "queues.kafka.producer.NewSyncProducer"
+
+	if pcfg.TransactionalID != "" {
+		return nil, fmt.Errorf("%s: transactional id is not supported", op)
+	}
+
+	cfg, err := newProducerSaramaConfig(pcfg)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	p, err := sarama.NewSyncProducer(pcfg.Brokers, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return p, nil
+}
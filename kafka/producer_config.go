@@ -0,0 +1,150 @@
+package kafka
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/IBM/sarama"
+)
+
+var producerAcks = map[Acks]sarama.RequiredAcks{
+	AcksAll:    sarama.WaitForAll,
+	AcksLeader: sarama.WaitForLocal,
+	AcksNone:   sarama.NoResponse,
+}
+
+var producerCompressions = map[Compression]sarama.CompressionCodec{
+	CompressionNone:   sarama.CompressionNone,
+	CompressionGZIP:   sarama.CompressionGZIP,
+	CompressionSnappy: sarama.CompressionSnappy,
+	CompressionLZ4:    sarama.CompressionLZ4,
+	CompressionZSTD:   sarama.CompressionZSTD,
+}
+
+var producerPartitioners = map[Partitioner]sarama.PartitionerConstructor{
+	PartitionerHash:       sarama.NewHashPartitioner,
+	PartitionerRandom:     sarama.NewRandomPartitioner,
+	PartitionerRoundRobin: sarama.NewRoundRobinPartitioner,
+	PartitionerManual:     sarama.NewManualPartitioner,
+}
+
+func newProducerSaramaConfig(pcfg ProducerConfig) (*sarama.Config, error) {
+
+	var errs []string
+
+	cfg := sarama.NewConfig()
+	cfg.Version = sarama.V4_1_0_0
+	cfg.Producer.Return.Successes = true
+	cfg.Producer.Retry.Max = 5
+	cfg.Producer.RequiredAcks = sarama.WaitForAll
+	cfg.Producer.Flush.MaxMessages = pcfg.MaxMessages
+	cfg.Producer.Flush.Frequency = pcfg.Frequency
+
+	if len(pcfg.Brokers) == 0 {
+		errs = append(errs, "brokers must not be empty")
+	}
+
+	if pcfg.Version != "" {
+		version, err := sarama.ParseKafkaVersion(pcfg.Version)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("version: %v", err))
+		}
+		cfg.Version = version
+	}
+
+	if pcfg.ClientID != "" {
+		cfg.ClientID = pcfg.ClientID
+	}
+
+	if pcfg.Acks != "" {
+		acks, ok := producerAcks[pcfg.Acks]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("unknown acks: %q", pcfg.Acks))
+		}
+		cfg.Producer.RequiredAcks = acks
+	}
+
+	if pcfg.RetryMax != nil {
+		if *pcfg.RetryMax < 0 {
+			errs = append(errs, "retryMax must be >= 0")
+		}
+		cfg.Producer.Retry.Max = *pcfg.RetryMax
+	}
+
+	if pcfg.RetryBackoff > 0 {
+		cfg.Producer.Retry.Backoff = pcfg.RetryBackoff
+	}
+
+	if pcfg.Compression != "" {
+		codec, ok := producerCompressions[pcfg.Compression]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("unknown compression: %q", pcfg.Compression))
+		}
+		cfg.Producer.Compression = codec
+	}
+
+	if pcfg.CompressionLevel != 0 {
+		cfg.Producer.CompressionLevel = pcfg.CompressionLevel
+	}
+
+	if pcfg.Partitioner != "" {
+		partitioner, ok := producerPartitioners[pcfg.Partitioner]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("unknown partitioner: %q", pcfg.Partitioner))
+		} else {
+			cfg.Producer.Partitioner = partitioner
+		}
+	}
+
+	if pcfg.MaxMessageBytes < 0 {
+		errs = append(errs, "maxMessageBytes must be >= 0")
+	} else if pcfg.MaxMessageBytes > 0 {
+		cfg.Producer.MaxMessageBytes = pcfg.MaxMessageBytes
+	}
+
+	if pcfg.Timeout > 0 {
+		cfg.Producer.Timeout = pcfg.Timeout
+	}
+
+	if pcfg.DialTimeout > 0 {
+		cfg.Net.DialTimeout = pcfg.DialTimeout
+	}
+
+	if pcfg.ReadTimeout > 0 {
+		cfg.Net.ReadTimeout = pcfg.ReadTimeout
+	}
+
+	if pcfg.WriteTimeout > 0 {
+		cfg.Net.WriteTimeout = pcfg.WriteTimeout
+	}
+
+	if pcfg.TransactionalID != "" {
+		if cfg.Producer.RequiredAcks != sarama.WaitForAll {
+			errs = append(errs, "transactional producer requires acks = all")
+		}
+		if cfg.Producer.Retry.Max < 1 {
+			errs = append(errs, "transactional producer requires retryMax >= 1")
+		}
+		cfg.Producer.Idempotent = true
+		cfg.Producer.Transaction.ID = pcfg.TransactionalID
+		cfg.Net.MaxOpenRequests = 1
+	}
+
+	err := applySecurity(cfg, pcfg.Security)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("security: %v", err))
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid config:\n - %s", strings.Join(errs, "\n - "))
+	}
+
+	if pcfg.Configure != nil {
+		pcfg.Configure(cfg)
+	}
+
+	// wrapped after Configure, so partitioner from Configure also respects explicit partition
+	cfg.Producer.Partitioner = NewExplicitPartitioner(cfg.Producer.Partitioner)
+
+	return cfg, nil
+}
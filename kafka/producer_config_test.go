@@ -0,0 +1,152 @@
+package kafka
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestNewProducerSaramaConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		pcfg    ProducerConfig
+		check   func(t *testing.T, cfg *sarama.Config)
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			pcfg: ProducerConfig{Brokers: []string{"localhost:9092"}},
+			check: func(t *testing.T, cfg *sarama.Config) {
+				if cfg.Producer.RequiredAcks != sarama.WaitForAll {
+					t.Errorf("acks = %v, want WaitForAll", cfg.Producer.RequiredAcks)
+				}
+				if cfg.Producer.Retry.Max != 5 {
+					t.Errorf("retry max = %d, want 5", cfg.Producer.Retry.Max)
+				}
+				if cfg.Version != sarama.V4_1_0_0 {
+					t.Errorf("version = %s, want %s", cfg.Version, sarama.V4_1_0_0)
+				}
+			},
+		},
+		{
+			name: "all settings",
+			pcfg: ProducerConfig{
+				Brokers:         []string{"localhost:9092"},
+				Version:         "3.6.0",
+				ClientID:        "orders",
+				Acks:            AcksLeader,
+				RetryMax:        intPtr(3),
+				RetryBackoff:    time.Second,
+				Compression:     CompressionLZ4,
+				MaxMessageBytes: 2000000,
+				Timeout:         5 * time.Second,
+				DialTimeout:     time.Second,
+			},
+			check: func(t *testing.T, cfg *sarama.Config) {
+				if cfg.Version != sarama.V3_6_0_0 {
+					t.Errorf("version = %s, want 3.6.0", cfg.Version)
+				}
+				if cfg.ClientID != "orders" {
+					t.Errorf("client id = %q, want orders", cfg.ClientID)
+				}
+				if cfg.Producer.RequiredAcks != sarama.WaitForLocal {
+					t.Errorf("acks = %v, want WaitForLocal", cfg.Producer.RequiredAcks)
+				}
+				if cfg.Producer.Retry.Max != 3 || cfg.Producer.Retry.Backoff != time.Second {
+					t.Errorf("retry = %d/%s, want 3/1s", cfg.Producer.Retry.Max, cfg.Producer.Retry.Backoff)
+				}
+				if cfg.Producer.Compression != sarama.CompressionLZ4 {
+					t.Errorf("compression = %v, want lz4", cfg.Producer.Compression)
+				}
+				if cfg.Producer.MaxMessageBytes != 2000000 {
+					t.Errorf("max message bytes = %d, want 2000000", cfg.Producer.MaxMessageBytes)
+				}
+				if cfg.Producer.Timeout != 5*time.Second || cfg.Net.DialTimeout != time.Second {
+					t.Errorf("timeouts = %s/%s, want 5s/1s", cfg.Producer.Timeout, cfg.Net.DialTimeout)
+				}
+			},
+		},
+		{
+			name: "retries disabled",
+			pcfg: ProducerConfig{Brokers: []string{"localhost:9092"}, RetryMax: intPtr(0)},
+			check: func(t *testing.T, cfg *sarama.Config) {
+				if cfg.Producer.Retry.Max != 0 {
+					t.Errorf("retry max = %d, want 0", cfg.Producer.Retry.Max)
+				}
+			},
+		},
+		{
+			name: "transactional",
+			pcfg: ProducerConfig{Brokers: []string{"localhost:9092"}, TransactionalID: "relay-1"},
+			check: func(t *testing.T, cfg *sarama.Config) {
+				if !cfg.Producer.Idempotent || cfg.Producer.Transaction.ID != "relay-1" || cfg.Net.MaxOpenRequests != 1 {
+					t.Errorf("transactional producer is not configured")
+				}
+			},
+		},
+		{
+			name: "configure hook is applied and partitioner is wrapped",
+			pcfg: ProducerConfig{
+				Brokers: []string{"localhost:9092"},
+				Configure: func(cfg *sarama.Config) {
+					cfg.ClientID = "from-hook"
+					cfg.Producer.Partitioner = sarama.NewRoundRobinPartitioner
+				},
+			},
+			check: func(t *testing.T, cfg *sarama.Config) {
+				if cfg.ClientID != "from-hook" {
+					t.Errorf("client id = %q, want from-hook", cfg.ClientID)
+				}
+				if _, ok := cfg.Producer.Partitioner("topic").(*explicitPartitioner); !ok {
+					t.Errorf("partitioner is not wrapped with explicit partitioner")
+				}
+			},
+		},
+		{name: "no brokers", pcfg: ProducerConfig{}, wantErr: "brokers"},
+		{name: "bad version", pcfg: ProducerConfig{Brokers: []string{"b"}, Version: "x"}, wantErr: "version"},
+		{name: "unknown acks", pcfg: ProducerConfig{Brokers: []string{"b"}, Acks: "some"}, wantErr: "acks"},
+		{name: "negative retries", pcfg: ProducerConfig{Brokers: []string{"b"}, RetryMax: intPtr(-1)}, wantErr: "retryMax"},
+		{name: "unknown compression", pcfg: ProducerConfig{Brokers: []string{"b"}, Compression: "brotli"}, wantErr: "compression"},
+		{name: "unknown partitioner", pcfg: ProducerConfig{Brokers: []string{"b"}, Partitioner: "sticky"}, wantErr: "partitioner"},
+		{name: "negative max message bytes", pcfg: ProducerConfig{Brokers: []string{"b"}, MaxMessageBytes: -1}, wantErr: "maxMessageBytes"},
+		{
+			name:    "transactional without acks all",
+			pcfg:    ProducerConfig{Brokers: []string{"b"}, TransactionalID: "relay-1", Acks: AcksLeader},
+			wantErr: "acks = all",
+		},
+		{
+			name:    "transactional without retries",
+			pcfg:    ProducerConfig{Brokers: []string{"b"}, TransactionalID: "relay-1", RetryMax: intPtr(0)},
+			wantErr: "retryMax >= 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := newProducerSaramaConfig(tt.pcfg)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if err = cfg.Validate(); err != nil {
+				t.Fatalf("sarama config is invalid: %v", err)
+			}
+
+			tt.check(t, cfg)
+		})
+	}
+}
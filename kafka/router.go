@@ -0,0 +1,150 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// Meta contains message data which is not a part of event payload
+type Meta struct {
+	EventID   string
+	EventType string
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       string
+	Headers   map[string]string
+	Timestamp time.Time
+}
+
+// FallbackFunc is called for messages with unknown event type
+type FallbackFunc func(ctx context.Context, msg *sarama.ConsumerMessage, meta Meta) error
+
+type routeFunc func(ctx context.Context, msg *sarama.ConsumerMessage, meta Meta) error
+
+// Router is consumer group handler, which routes messages by event_type header to typed handlers.
+// Message is marked only after successful handling, handler error stops the session
+// and message is consumed again after rebalance
+type Router struct {
+	mu       sync.RWMutex
+	routes   map[string]routeFunc
+	fallback FallbackFunc
+	log      *slog.Logger
+	// set by NewConsumerGroup from ConsumerGroupConfig.AutoCommit
+	autoCommit bool
+}
+
+type RouterOption func(*Router)
+
+// WithFallback sets handler for unknown event types, by default such messages are logged and skipped
+func WithFallback(fn FallbackFunc) RouterOption {
+	return func(r *Router) {
+		r.fallback = fn
+	}
+}
+
+func NewRouter(log *slog.Logger, opts ...RouterOption) *Router {
+	r := &Router{
+		routes: make(map[string]routeFunc),
+		log:    log,
+	}
+
+	r.fallback = r.skipUnknown
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Handle registers handler for event type, payload is decoded from json to T
+func Handle[T any](r *Router, eventType string, fn func(ctx context.Context, ev T, meta Meta) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.routes[eventType] = func(ctx context.Context, msg *sarama.ConsumerMessage, meta Meta) error {
+		var ev T
+
+		err := json.Unmarshal(msg.Value, &ev)
+
+		if err != nil {
+			return fmt.Errorf("decode %s: %w", eventType, err)
+		}
+
+		return fn(ctx, ev, meta)
+	}
+}
+
+func (r *Router) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (r *Router) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (r *Router) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	return consumeClaim(sess, claim, r, r.autoCommit)
+}
+
+func (r *Router) setAutoCommit(enabled bool) {
+	r.autoCommit = enabled
+}
+
+// Handle routes message to handler of its event type, so Router can be wrapped with middlewares
+func (r *Router) Handle(ctx context.Context, msg *sarama.ConsumerMessage) error {
+	meta := newMeta(msg)
+
+	r.mu.RLock()
+	route, ok := r.routes[meta.EventType]
+	r.mu.RUnlock()
+
+	if !ok {
+		return r.fallback(ctx, msg, meta)
+	}
+
+	return route(ctx, msg, meta)
+}
+
+func (r *Router) skipUnknown(ctx context.Context, msg *sarama.ConsumerMessage, meta Meta) error {
+	const op = "queues.kafka.router.skipUnknown"
+
+	r.log.Warn("skip message with unknown event type",
+		slog.String("op", op),
+		slog.String("event_type", meta.EventType),
+		slog.String("event_id", meta.EventID),
+		slog.String("topic", meta.Topic),
+		slog.Int("partition", int(meta.Partition)),
+		slog.Int64("offset", meta.Offset),
+	)
+
+	return nil
+}
+
+func newMeta(msg *sarama.ConsumerMessage) Meta {
+	headers := make(map[string]string, len(msg.Headers))
+
+	for _, h := range msg.Headers {
+		if h != nil {
+			headers[string(h.Key)] = string(h.Value)
+		}
+	}
+
+	return Meta{
+		EventID:   headers[HeaderEventID],
+		EventType: headers[HeaderEventType],
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       string(msg.Key),
+		Headers:   headers,
+		Timestamp: msg.Timestamp,
+	}
+}
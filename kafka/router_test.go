@@ -0,0 +1,167 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+type orderCreated struct {
+	ID     string `json:"id"`
+	Amount int    `json:"amount"`
+}
+
+func newTestMessage(eventType string, value string) *sarama.ConsumerMessage {
+	return &sarama.ConsumerMessage{
+		Topic:     "orders",
+		Partition: 1,
+		Offset:    42,
+		Key:       []byte("order-1"),
+		Value:     []byte(value),
+		Timestamp: time.Unix(100, 0),
+		Headers: []*sarama.RecordHeader{
+			{Key: []byte(HeaderEventID), Value: []byte("event-1")},
+			{Key: []byte(HeaderEventType), Value: []byte(eventType)},
+			{Key: []byte("tenant_id"), Value: []byte("tenant-1")},
+		},
+	}
+}
+
+func TestRouterHandle(t *testing.T) {
+	errHandler := errors.New("handler error")
+
+	tests := []struct {
+		name          string
+		eventType     string
+		value         string
+		handlerErr    error
+		fallback      bool
+		wantErr       error
+		wantDecodeErr bool
+		wantHandled   bool
+		wantFallback  bool
+	}{
+		{name: "registered type", eventType: "order.created", value: `{"id":"order-1","amount":10}`, wantHandled: true},
+		{name: "handler error", eventType: "order.created", value: `{"id":"order-1"}`, handlerErr: errHandler, wantErr: errHandler, wantHandled: true},
+		{name: "malformed payload", eventType: "order.created", value: `{"id":`, wantDecodeErr: true},
+		{name: "unknown type is skipped by default", eventType: "order.deleted", value: `{}`},
+		{name: "unknown type goes to fallback", eventType: "order.deleted", value: `{}`, fallback: true, wantFallback: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handled, fallbackCalled bool
+
+			var opts []RouterOption
+
+			if tt.fallback {
+				opts = append(opts, WithFallback(func(ctx context.Context, msg *sarama.ConsumerMessage, meta Meta) error {
+					fallbackCalled = true
+					if meta.EventType != tt.eventType {
+						t.Errorf("fallback event type = %q, want %q", meta.EventType, tt.eventType)
+					}
+					return nil
+				}))
+			}
+
+			r := NewRouter(slog.New(slog.NewTextHandler(io.Discard, nil)), opts...)
+
+			Handle(r, "order.created", func(ctx context.Context, ev orderCreated, meta Meta) error {
+				handled = true
+
+				if ev.ID != "order-1" {
+					t.Errorf("event id = %q, want order-1", ev.ID)
+				}
+
+				want := Meta{
+					EventID:   "event-1",
+					EventType: "order.created",
+					Topic:     "orders",
+					Partition: 1,
+					Offset:    42,
+					Key:       "order-1",
+					Timestamp: time.Unix(100, 0),
+				}
+
+				if meta.EventID != want.EventID || meta.EventType != want.EventType || meta.Topic != want.Topic ||
+					meta.Partition != want.Partition || meta.Offset != want.Offset || meta.Key != want.Key ||
+					!meta.Timestamp.Equal(want.Timestamp) || meta.Headers["tenant_id"] != "tenant-1" {
+					t.Errorf("meta = %+v, want %+v with tenant_id header", meta, want)
+				}
+
+				return tt.handlerErr
+			})
+
+			err := r.Handle(context.Background(), newTestMessage(tt.eventType, tt.value))
+
+			switch {
+			case tt.wantDecodeErr:
+				if err == nil {
+					t.Fatal("expected decode error")
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			if handled != tt.wantHandled {
+				t.Errorf("handled = %v, want %v", handled, tt.wantHandled)
+			}
+
+			if fallbackCalled != tt.wantFallback {
+				t.Errorf("fallback called = %v, want %v", fallbackCalled, tt.wantFallback)
+			}
+		})
+	}
+}
+
+func TestRouterConsumeClaimCommit(t *testing.T) {
+	errHandler := errors.New("handler error")
+
+	tests := []struct {
+		name        string
+		autoCommit  bool
+		handlerErr  error
+		wantMarked  int
+		wantCommits int
+	}{
+		{name: "manual commit after each message", autoCommit: false, wantMarked: 2, wantCommits: 2},
+		{name: "auto commit leaves commit to sarama", autoCommit: true, wantMarked: 2, wantCommits: 0},
+		{name: "failed message is not marked", autoCommit: false, handlerErr: errHandler},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter(slog.New(slog.NewTextHandler(io.Discard, nil)))
+			r.setAutoCommit(tt.autoCommit)
+
+			Handle(r, "order.created", func(ctx context.Context, ev orderCreated, meta Meta) error {
+				return tt.handlerErr
+			})
+
+			sess := newFakeSession()
+			claim := newFakeClaim(
+				newTestMessage("order.created", `{"id":"order-1"}`),
+				newTestMessage("order.created", `{"id":"order-2"}`),
+			)
+
+			err := r.ConsumeClaim(sess, claim)
+
+			if !errors.Is(err, tt.handlerErr) {
+				t.Fatalf("err = %v, want %v", err, tt.handlerErr)
+			}
+
+			if len(sess.marked) != tt.wantMarked {
+				t.Errorf("marked = %d, want %d", len(sess.marked), tt.wantMarked)
+			}
+
+			if sess.commits != tt.wantCommits {
+				t.Errorf("commits = %d, want %d", sess.commits, tt.wantCommits)
+			}
+		})
+	}
+}
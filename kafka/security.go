@@ -0,0 +1,168 @@
+package kafka
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/IBM/sarama"
+	"github.com/xdg-go/scram"
+)
+
+type SASLMechanism string
+
+const (
+	SASLPlain       SASLMechanism = sarama.SASLTypePlaintext
+	SASLScramSHA256 SASLMechanism = sarama.SASLTypeSCRAMSHA256
+	SASLScramSHA512 SASLMechanism = sarama.SASLTypeSCRAMSHA512
+	SASLOAuthBearer SASLMechanism = sarama.SASLTypeOAuth
+)
+
+// SecurityConfig is shared by producer and consumer group, nil = plaintext connection
+type SecurityConfig struct {
+	TLS  *TLSConfig
+	SASL *SASLConfig
+}
+
+type TLSConfig struct {
+	// PEM file with CA certificates, default = system pool
+	CAFile string
+	// PEM files with client certificate and key for mTLS, optional
+	CertFile string
+	KeyFile  string
+	// Server name for certificate verification, default = broker host
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+type SASLConfig struct {
+	Mechanism SASLMechanism
+	// For PLAIN and SCRAM mechanisms
+	User     string
+	Password string
+	// For OAUTHBEARER mechanism
+	TokenProvider sarama.AccessTokenProvider
+}
+
+// TokenProviderFunc allows to use function as OAUTHBEARER token provider
+type TokenProviderFunc func() (*sarama.AccessToken, error)
+
+func (f TokenProviderFunc) Token() (*sarama.AccessToken, error) {
+	return f()
+}
+
+func applySecurity(cfg *sarama.Config, sec *SecurityConfig) error {
+
+	if sec == nil {
+		return nil
+	}
+
+	if sec.TLS != nil {
+		tlsCfg, err := newTLSConfig(sec.TLS)
+		if err != nil {
+			return fmt.Errorf("tls: %w", err)
+		}
+		cfg.Net.TLS.Enable = true
+		cfg.Net.TLS.Config = tlsCfg
+	}
+
+	if sec.SASL != nil {
+		err := applySASL(cfg, sec.SASL)
+		if err != nil {
+			return fmt.Errorf("sasl: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func newTLSConfig(c *TLSConfig) (*tls.Config, error) {
+
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CAFile != "" {
+		caPEM, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in %s", c.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsCfg, nil
+}
+
+func applySASL(cfg *sarama.Config, c *SASLConfig) error {
+
+	cfg.Net.SASL.Enable = true
+	cfg.Net.SASL.Handshake = true
+	cfg.Net.SASL.Mechanism = sarama.SASLMechanism(c.Mechanism)
+
+	switch c.Mechanism {
+	case SASLPlain:
+		cfg.Net.SASL.User = c.User
+		cfg.Net.SASL.Password = c.Password
+	case SASLScramSHA256:
+		cfg.Net.SASL.User = c.User
+		cfg.Net.SASL.Password = c.Password
+		cfg.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+			return &scramClient{HashGeneratorFcn: scram.HashGeneratorFcn(sha256.New)}
+		}
+	case SASLScramSHA512:
+		cfg.Net.SASL.User = c.User
+		cfg.Net.SASL.Password = c.Password
+		cfg.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+			return &scramClient{HashGeneratorFcn: scram.HashGeneratorFcn(sha512.New)}
+		}
+	case SASLOAuthBearer:
+		if c.TokenProvider == nil {
+			return errors.New("token provider is required for OAUTHBEARER")
+		}
+		cfg.Net.SASL.TokenProvider = c.TokenProvider
+	default:
+		return fmt.Errorf("unknown mechanism: %q", c.Mechanism)
+	}
+
+	return nil
+}
+
+type scramClient struct {
+	*scram.ClientConversation
+	scram.HashGeneratorFcn
+}
+
+func (c *scramClient) Begin(userName, password, authzID string) error {
+	client, err := c.HashGeneratorFcn.NewClient(userName, password, authzID)
+	if err != nil {
+		return err
+	}
+	c.ClientConversation = client.NewConversation()
+	return nil
+}
+
+func (c *scramClient) Step(challenge string) (string, error) {
+	return c.ClientConversation.Step(challenge)
+}
+
+func (c *scramClient) Done() bool {
+	return c.ClientConversation.Done()
+}
@@ -0,0 +1,276 @@
+package kafka
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+type testCert struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certFile string
+	keyFile  string
+}
+
+// newTestCert creates certificate signed by parent, self-signed CA if parent is nil
+func newTestCert(t *testing.T, dir string, name string, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signerCert, signerKey := tmpl, key
+
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signerCert, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &testCert{
+		cert:     cert,
+		key:      key,
+		certFile: filepath.Join(dir, name+".crt"),
+		keyFile:  filepath.Join(dir, name+".key"),
+	}
+
+	writePEM(t, c.certFile, "CERTIFICATE", der)
+	writePEM(t, c.keyFile, "EC PRIVATE KEY", keyDER)
+
+	return c
+}
+
+func writePEM(t *testing.T, path string, blockType string, der []byte) {
+	t.Helper()
+
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplySASL(t *testing.T) {
+	token := TokenProviderFunc(func() (*sarama.AccessToken, error) {
+		return &sarama.AccessToken{Token: "token"}, nil
+	})
+
+	tests := []struct {
+		name      string
+		sasl      *SASLConfig
+		wantScram bool
+		wantErr   string
+	}{
+		{name: "plain", sasl: &SASLConfig{Mechanism: SASLPlain, User: "user", Password: "pass"}},
+		{name: "scram sha256", sasl: &SASLConfig{Mechanism: SASLScramSHA256, User: "user", Password: "pass"}, wantScram: true},
+		{name: "scram sha512", sasl: &SASLConfig{Mechanism: SASLScramSHA512, User: "user", Password: "pass"}, wantScram: true},
+		{name: "oauthbearer", sasl: &SASLConfig{Mechanism: SASLOAuthBearer, TokenProvider: token}},
+		{name: "oauthbearer without token provider", sasl: &SASLConfig{Mechanism: SASLOAuthBearer}, wantErr: "token provider"},
+		{name: "unknown mechanism", sasl: &SASLConfig{Mechanism: "GSSAPI"}, wantErr: "unknown mechanism"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := newProducerSaramaConfig(ProducerConfig{
+				Brokers:  []string{"localhost:9092"},
+				Security: &SecurityConfig{SASL: tt.sasl},
+			})
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if err = cfg.Validate(); err != nil {
+				t.Fatalf("sarama config is invalid: %v", err)
+			}
+
+			if !cfg.Net.SASL.Enable || cfg.Net.SASL.Mechanism != sarama.SASLMechanism(tt.sasl.Mechanism) {
+				t.Errorf("sasl = %v/%s, want enabled %s", cfg.Net.SASL.Enable, cfg.Net.SASL.Mechanism, tt.sasl.Mechanism)
+			}
+
+			if tt.sasl.Mechanism != SASLOAuthBearer && (cfg.Net.SASL.User != "user" || cfg.Net.SASL.Password != "pass") {
+				t.Errorf("credentials are not set")
+			}
+
+			if !tt.wantScram {
+				return
+			}
+
+			client := cfg.Net.SASL.SCRAMClientGeneratorFunc()
+
+			if err = client.Begin("user", "pass", ""); err != nil {
+				t.Fatalf("scram begin: %v", err)
+			}
+
+			first, err := client.Step("")
+
+			if err != nil || !strings.HasPrefix(first, "n,,n=user,r=") {
+				t.Fatalf("scram client first message = %q, %v", first, err)
+			}
+		})
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	ca := newTestCert(t, dir, "ca", nil)
+	client := newTestCert(t, dir, "client", ca)
+
+	emptyFile := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(emptyFile, []byte("no certificates"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		tls        *TLSConfig
+		wantRoots  bool
+		wantClient bool
+		wantErr    bool
+	}{
+		{name: "system pool", tls: &TLSConfig{}},
+		{name: "custom ca", tls: &TLSConfig{CAFile: ca.certFile}, wantRoots: true},
+		{name: "mtls", tls: &TLSConfig{CAFile: ca.certFile, CertFile: client.certFile, KeyFile: client.keyFile}, wantRoots: true, wantClient: true},
+		{name: "missing ca file", tls: &TLSConfig{CAFile: filepath.Join(dir, "missing.pem")}, wantErr: true},
+		{name: "ca file without certificates", tls: &TLSConfig{CAFile: emptyFile}, wantErr: true},
+		{name: "certificate without key", tls: &TLSConfig{CertFile: client.certFile}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := sarama.NewConfig()
+
+			err := applySecurity(cfg, &SecurityConfig{TLS: tt.tls})
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !cfg.Net.TLS.Enable {
+				t.Fatal("tls is not enabled")
+			}
+
+			if got := cfg.Net.TLS.Config.RootCAs != nil; got != tt.wantRoots {
+				t.Errorf("custom roots = %v, want %v", got, tt.wantRoots)
+			}
+
+			if got := len(cfg.Net.TLS.Config.Certificates) == 1; got != tt.wantClient {
+				t.Errorf("client certificate = %v, want %v", got, tt.wantClient)
+			}
+		})
+	}
+}
+
+func TestTLSWithMockBroker(t *testing.T) {
+	dir := t.TempDir()
+
+	ca := newTestCert(t, dir, "ca", nil)
+	server := newTestCert(t, dir, "server", ca)
+	client := newTestCert(t, dir, "client", ca)
+
+	serverCert, err := tls.LoadX509KeyPair(server.certFile, server.keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	broker := sarama.NewMockBrokerListener(t, 1, listener)
+	defer broker.Close()
+
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()),
+	})
+
+	cfg, err := newProducerSaramaConfig(ProducerConfig{
+		Brokers: []string{broker.Addr()},
+		Security: &SecurityConfig{TLS: &TLSConfig{
+			CAFile:   ca.certFile,
+			CertFile: client.certFile,
+			KeyFile:  client.keyFile,
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Metadata.Retry.Max = 0
+
+	c, err := sarama.NewClient([]string{broker.Addr()}, cfg)
+	if err != nil {
+		t.Fatalf("client over tls: %v", err)
+	}
+
+	if err = c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
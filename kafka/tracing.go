@@ -0,0 +1,46 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+)
+
+// ContextFromMessage extracts trace context from message headers using global otel propagator
+func ContextFromMessage(ctx context.Context, msg *sarama.ConsumerMessage) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, &headersCarrier{headers: msg.Headers})
+}
+
+type headersCarrier struct {
+	headers []*sarama.RecordHeader
+}
+
+func (c *headersCarrier) Get(key string) string {
+	for _, h := range c.headers {
+		if h != nil && string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c *headersCarrier) Set(key string, value string) {
+	for _, h := range c.headers {
+		if h != nil && string(h.Key) == key {
+			h.Value = []byte(value)
+			return
+		}
+	}
+	c.headers = append(c.headers, &sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+func (c *headersCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.headers))
+	for _, h := range c.headers {
+		if h != nil {
+			keys = append(keys, string(h.Key))
+		}
+	}
+	return keys
+}
@@ -3,6 +3,7 @@ package outbox
 import (
 	"fmt"
 	"math"
+	"math/rand/v2"
 	"strings"
 	"time"
 )
@@ -11,47 +12,146 @@ type Config struct {
 	// Limit of events to receive, min = 1, Max = 1000
 	Limit int
 
-	// Num of workers for publish events, min = 1, max = 32
-	// Workers int
+	// Num of workers for publish events, min = 1, max = 32, 0 = 1
+	Workers int
 
 	// Event processing interval, min = 100ms
 	Interval time.Duration
 
+	// Adaptive polling: after full batch next processing starts immediately,
+	// after empty batch interval is doubled up to MaxInterval. 0 = disabled, min = Interval
+	MaxInterval time.Duration
+
 	// Event reserve duration, min = 15s
 	ReserveDuration time.Duration
 
 	// Timeout for processing method, min = 200 ms
 	ProcessTimeout time.Duration
+
+	// Max send attempts, after which event moves to failed status, min = 1, max = 100, 0 = 10
+	MaxAttempts int
+
+	// Delay policy between send attempts of failed event, zero value = DefaultBackoffConfig
+	Backoff BackoffConfig
+
+	// Per-aggregate ordering: event is not reserved while there is older new or failed event
+	// with the same aggregate_id, so failed event blocks next events of its aggregate until it is sent
+	Ordered bool
+
+	// Interval of moving expired unsent events to expired status, requires ExpiringAdapter.
+	// Expired events are never published, even if disabled. 0 = disabled, min = 1s
+	ExpireInterval time.Duration
+}
+
+type BackoffConfig struct {
+	// Delay after first failed attempt, min = 100ms
+	Base time.Duration
+
+	// Delay multiplier for each next attempt, min = 1
+	Multiplier float64
+
+	// Max delay, min = Base
+	Max time.Duration
+
+	// Random part of delay, min = 0, max = 1 (0.2 -> delay in [0.8*d;d])
+	Jitter float64
+}
+
+var DefaultBackoffConfig = BackoffConfig{
+	Base:       time.Second,
+	Multiplier: 2,
+	Max:        5 * time.Minute,
+	Jitter:     0.2,
+}
+
+// Delay returns delay before next send attempt, attempts - num of already failed attempts
+func (b BackoffConfig) Delay(attempts int) time.Duration {
+
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := float64(b.Base) * math.Pow(b.Multiplier, float64(attempts-1))
+
+	delay = min(delay, float64(b.Max))
+
+	delay -= delay * b.Jitter * rand.Float64()
+
+	return time.Duration(delay)
 }
 
 var SmallBatchConfig = Config{
 	Limit:           50,
+	Workers:         1,
 	Interval:        250 * time.Millisecond,
 	ReserveDuration: 15 * time.Second,
 	ProcessTimeout:  300 * time.Millisecond,
+	MaxAttempts:     10,
+	Backoff:         DefaultBackoffConfig,
+	ExpireInterval:  time.Minute,
 }
 
 var MediumBatchConfig = Config{
 	Limit:           200,
+	Workers:         2,
 	Interval:        450 * time.Millisecond,
 	ReserveDuration: 30 * time.Second,
 	ProcessTimeout:  530 * time.Millisecond,
+	MaxAttempts:     10,
+	Backoff:         DefaultBackoffConfig,
+	ExpireInterval:  time.Minute,
 }
 
 var LargeBatchConfig = Config{
 	Limit:           500,
+	Workers:         4,
 	Interval:        620 * time.Second,
 	ReserveDuration: 45 * time.Second,
 	ProcessTimeout:  720 * time.Second,
+	MaxAttempts:     10,
+	Backoff:         DefaultBackoffConfig,
+	ExpireInterval:  time.Minute,
+}
+
+const (
+	defaultWorkers     = 1
+	defaultMaxAttempts = 10
+)
+
+// withDefaults returns copy of config, where zero values of fields added after first release are replaced with defaults
+func (c Config) withDefaults() Config {
+
+	if c.Workers == 0 {
+		c.Workers = defaultWorkers
+	}
+
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = defaultMaxAttempts
+	}
+
+	if c.Backoff == (BackoffConfig{}) {
+		c.Backoff = DefaultBackoffConfig
+	}
+
+	return c
 }
 
 func validateConfig(cfg *Config) error {
 	const (
 		minLimit          = 1
 		maxLimit          = 1000
+		minWorkers        = 1
+		maxWorkers        = 32
 		minInterval       = 100 * time.Millisecond
+		minExpireInterval = time.Second
 		minReserve        = 15 * time.Second
 		minProcessTimeout = 200 * time.Millisecond
+		minAttempts       = 1
+		maxAttempts       = 100
+		minBackoffBase    = 100 * time.Millisecond
+		minMultiplier     = 1
+		minJitter         = 0
+		maxJitter         = 1
 	)
 
 	var errs []string
@@ -60,10 +160,22 @@ func validateConfig(cfg *Config) error {
 		errs = append(errs, fmt.Sprintf("limit must be in [%d;%d]", minLimit, maxLimit))
 	}
 
+	if cfg.Workers < minWorkers || cfg.Workers > maxWorkers {
+		errs = append(errs, fmt.Sprintf("workers must be in [%d;%d]", minWorkers, maxWorkers))
+	}
+
 	if cfg.Interval < minInterval {
 		errs = append(errs, fmt.Sprintf("interval must be >= %s", minInterval))
 	}
 
+	if cfg.MaxInterval != 0 && cfg.MaxInterval < cfg.Interval {
+		errs = append(errs, "maxInterval must be 0 or >= interval")
+	}
+
+	if cfg.ExpireInterval != 0 && cfg.ExpireInterval < minExpireInterval {
+		errs = append(errs, fmt.Sprintf("expireInterval must be 0 or >= %s", minExpireInterval))
+	}
+
 	if cfg.ReserveDuration < minReserve {
 		errs = append(errs, fmt.Sprintf("reserveDuration must be >= %s", minReserve))
 	}
@@ -72,6 +184,26 @@ func validateConfig(cfg *Config) error {
 		errs = append(errs, fmt.Sprintf("processTimeout must be >= %s", minProcessTimeout))
 	}
 
+	if cfg.MaxAttempts < minAttempts || cfg.MaxAttempts > maxAttempts {
+		errs = append(errs, fmt.Sprintf("maxAttempts must be in [%d;%d]", minAttempts, maxAttempts))
+	}
+
+	if cfg.Backoff.Base < minBackoffBase {
+		errs = append(errs, fmt.Sprintf("backoff.base must be >= %s", minBackoffBase))
+	}
+
+	if cfg.Backoff.Multiplier < minMultiplier {
+		errs = append(errs, fmt.Sprintf("backoff.multiplier must be >= %d", minMultiplier))
+	}
+
+	if cfg.Backoff.Max < cfg.Backoff.Base {
+		errs = append(errs, "backoff.max must be >= backoff.base")
+	}
+
+	if cfg.Backoff.Jitter < minJitter || cfg.Backoff.Jitter > maxJitter {
+		errs = append(errs, fmt.Sprintf("backoff.jitter must be in [%d;%d]", minJitter, maxJitter))
+	}
+
 	if len(errs) > 0 {
 		return fmt.Errorf("invalid config:\n - %s", strings.Join(errs, "\n - "))
 	}
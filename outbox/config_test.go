@@ -0,0 +1,135 @@
+package outbox
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackoffConfigDelay(t *testing.T) {
+	cfg := BackoffConfig{
+		Base:       time.Second,
+		Multiplier: 2,
+		Max:        10 * time.Second,
+	}
+
+	tests := []struct {
+		name     string
+		attempts int
+		want     time.Duration
+	}{
+		{name: "zero attempts as first", attempts: 0, want: time.Second},
+		{name: "first attempt", attempts: 1, want: time.Second},
+		{name: "second attempt", attempts: 2, want: 2 * time.Second},
+		{name: "third attempt", attempts: 3, want: 4 * time.Second},
+		{name: "limited by max", attempts: 10, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.Delay(tt.attempts); got != tt.want {
+				t.Errorf("Delay(%d) = %s, want %s", tt.attempts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoffConfigDelayJitter(t *testing.T) {
+	cfg := BackoffConfig{
+		Base:       time.Second,
+		Multiplier: 2,
+		Max:        time.Minute,
+		Jitter:     0.2,
+	}
+
+	tests := []struct {
+		name     string
+		attempts int
+		min      time.Duration
+		max      time.Duration
+	}{
+		{name: "first attempt", attempts: 1, min: 800 * time.Millisecond, max: time.Second},
+		{name: "third attempt", attempts: 3, min: 3200 * time.Millisecond, max: 4 * time.Second},
+		{name: "limited by max", attempts: 20, min: 48 * time.Second, max: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 100 {
+				got := cfg.Delay(tt.attempts)
+				if got < tt.min || got > tt.max {
+					t.Fatalf("Delay(%d) = %s, want in [%s;%s]", tt.attempts, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr string
+	}{
+		{name: "small preset", modify: func(c *Config) {}},
+		{name: "limit", modify: func(c *Config) { c.Limit = 0 }, wantErr: "limit"},
+		{name: "workers", modify: func(c *Config) { c.Workers = 33 }, wantErr: "workers"},
+		{name: "max interval", modify: func(c *Config) { c.MaxInterval = c.Interval / 2 }, wantErr: "maxInterval"},
+		{name: "expire interval", modify: func(c *Config) { c.ExpireInterval = time.Millisecond }, wantErr: "expireInterval"},
+		{name: "max attempts", modify: func(c *Config) { c.MaxAttempts = 0 }, wantErr: "maxAttempts"},
+		{name: "backoff max", modify: func(c *Config) { c.Backoff.Max = c.Backoff.Base / 2 }, wantErr: "backoff.max"},
+		{name: "backoff jitter", modify: func(c *Config) { c.Backoff.Jitter = 1.5 }, wantErr: "backoff.jitter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := SmallBatchConfig
+			tt.modify(&cfg)
+
+			err := validateConfig(&cfg)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigWithDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want Config
+	}{
+		{
+			name: "zero fields are defaulted",
+			cfg:  Config{Limit: 50},
+			want: Config{Limit: 50, Workers: 1, MaxAttempts: 10, Backoff: DefaultBackoffConfig},
+		},
+		{
+			name: "set fields are not changed",
+			cfg:  Config{Workers: 4, MaxAttempts: 3, Backoff: BackoffConfig{Base: time.Second, Multiplier: 1, Max: time.Second}},
+			want: Config{Workers: 4, MaxAttempts: 3, Backoff: BackoffConfig{Base: time.Second, Multiplier: 1, Max: time.Second}},
+		},
+		{
+			name: "partially set backoff is not changed",
+			cfg:  Config{Workers: 1, MaxAttempts: 1, Backoff: BackoffConfig{Base: time.Second}},
+			want: Config{Workers: 1, MaxAttempts: 1, Backoff: BackoffConfig{Base: time.Second}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.withDefaults(); got != tt.want {
+				t.Errorf("withDefaults() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package outbox
 
 import (
+	"errors"
 	"log/slog"
 	"sync"
+
+	kafkalib "github.com/fedotovmax/kafka-lib"
 )
 
 func (a *Outbox) errorsMonitoring(wg *sync.WaitGroup) {
@@ -10,7 +13,7 @@ func (a *Outbox) errorsMonitoring(wg *sync.WaitGroup) {
 
 	log := a.log.With(slog.String("op", op))
 
-	eventsErrors := a.kafka.GetErrors(a.ctx)
+	eventsErrors := a.publisher.Errors(a.ctx)
 
 	wg.Add(1)
 	go func() {
@@ -20,17 +23,29 @@ func (a *Outbox) errorsMonitoring(wg *sync.WaitGroup) {
 			case <-a.ctx.Done():
 				log.Info("monitoring [errors] stopped: ctx closed")
 				return
-			case event, ok := <-eventsErrors:
+			case publishErr, ok := <-eventsErrors:
 				if !ok {
 					log.Info("monitoring [errors] stopped: channel closed")
 					return
 				}
+				a.metrics.EventFailed()
+
+				event := newFailedEvent(publishErr.Event, publishErr.Err)
+
 				log.Error("event send failed",
 					slog.String("event_id", event.ID), slog.String("error", event.Error.Error()))
 
 				err := a.fail(event)
 
+				if errors.Is(err, kafkalib.ErrMissUpdate) {
+					a.metrics.ReservationLost()
+					log.Warn("reservation is lost, confirm send fail skipped",
+						slog.String("event_id", event.ID))
+					continue
+				}
+
 				if err != nil {
+					a.metrics.ConfirmError()
 					log.Error("error when confirm send fail", slog.String("error", err.Error()))
 				}
 			}
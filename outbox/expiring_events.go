@@ -0,0 +1,62 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+func (a *Outbox) expiringEvents(wg *sync.WaitGroup) {
+	const op = "outbox.expiringEvents"
+
+	log := a.log.With(slog.String("op", op))
+
+	if a.cfg.ExpireInterval == 0 {
+		return
+	}
+
+	expirer, ok := a.adapter.(ExpiringAdapter)
+
+	if !ok {
+		log.Warn("adapter does not implement ExpiringAdapter, event expiring disabled")
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(a.cfg.ExpireInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-a.ctx.Done():
+				log.Info("event expiring stopped")
+				return
+			case <-ticker.C:
+				a.expire(log, expirer)
+			}
+		}
+	}()
+}
+
+func (a *Outbox) expire(log *slog.Logger, expirer ExpiringAdapter) {
+
+	queriesCtx, cancelQueriesCtx := context.WithTimeout(a.ctx, a.cfg.ProcessTimeout)
+	defer cancelQueriesCtx()
+
+	ids, err := expirer.ExpireEvents(queriesCtx, a.cfg.Limit)
+
+	if err != nil {
+		log.Error("error when expiring events", slog.String("error", err.Error()))
+		return
+	}
+
+	if len(ids) == 0 {
+		return
+	}
+
+	a.metrics.EventsExpired(len(ids))
+
+	log.Warn("unsent events expired", slog.Int("count", len(ids)), slog.Any("event_ids", ids))
+}
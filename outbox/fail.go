@@ -11,7 +11,7 @@ func (a *Outbox) fail(ev *failedEvent) error {
 	queriesCtx, cancelQueriesCtx := context.WithTimeout(a.ctx, a.cfg.ProcessTimeout)
 	defer cancelQueriesCtx()
 
-	err := a.adapter.ConfirmFailedEvent(queriesCtx, ev)
+	err := a.adapter.ConfirmFailedEvent(queriesCtx, ev, a.cfg.MaxAttempts, a.cfg.Backoff)
 
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -0,0 +1,72 @@
+package outbox
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func testConfig() *Config {
+	return &Config{
+		Limit:           10,
+		Interval:        100 * time.Millisecond,
+		ReserveDuration: 15 * time.Second,
+		ProcessTimeout:  time.Second,
+	}
+}
+
+type fakeAdapter struct {
+	reserve func(ctx context.Context, limit int) ([]Event, error)
+
+	mu        sync.Mutex
+	confirmed []string
+	failed    []string
+}
+
+func (f *fakeAdapter) ReserveNewEvents(ctx context.Context, limit int, reserveDuration time.Duration, ordered bool) ([]Event, error) {
+	if f.reserve == nil {
+		return nil, nil
+	}
+	return f.reserve(ctx, limit)
+}
+
+func (f *fakeAdapter) ConfirmEvent(ctx context.Context, ev SuccessEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.confirmed = append(f.confirmed, ev.GetID())
+	return nil
+}
+
+func (f *fakeAdapter) ConfirmFailedEvent(ctx context.Context, ev FailedEvent, maxAttempts int, backoff BackoffConfig) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failed = append(f.failed, ev.GetID())
+	return nil
+}
+
+type fakePublisher struct {
+	successes chan Event
+	errors    chan *PublishError
+}
+
+func newFakePublisher() *fakePublisher {
+	return &fakePublisher{successes: make(chan Event), errors: make(chan *PublishError)}
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, ev Event) error {
+	return nil
+}
+
+func (p *fakePublisher) Successes(ctx context.Context) <-chan Event {
+	return p.successes
+}
+
+func (p *fakePublisher) Errors(ctx context.Context) <-chan *PublishError {
+	return p.errors
+}
@@ -11,6 +11,20 @@ type CreateEvent struct {
 	ttype       string
 	createdAt   time.Time
 	payload     json.RawMessage
+	// W3C trace context (traceparent, tracestate, baggage)
+	traceContext map[string]string
+	// Custom kafka headers (tenant id, correlation id, schema version...)
+	headers map[string]string
+	// Kafka message key, default = aggregateID
+	partitionKey string
+	// Exact kafka partition, default = chosen by producer partitioner
+	partition *int32
+	// Event is not sent before this time, default = send immediately
+	deliverAfter time.Time
+	// Unsent event is moved to expired status after this time, default = never expires
+	expiresAt time.Time
+	// Deduplication key, event with existing key is not created again
+	idempotencyKey string
 }
 
 func NewCreateEventInput() *CreateEvent {
@@ -37,6 +51,41 @@ func (i *CreateEvent) SetCreatedAt(t time.Time) {
 	i.createdAt = t
 }
 
+func (i *CreateEvent) SetTraceContext(tc map[string]string) {
+	i.traceContext = tc
+}
+
+func (i *CreateEvent) SetHeaders(h map[string]string) {
+	i.headers = h
+}
+
+func (i *CreateEvent) SetHeader(key string, value string) {
+	if i.headers == nil {
+		i.headers = make(map[string]string)
+	}
+	i.headers[key] = value
+}
+
+func (i *CreateEvent) SetPartitionKey(k string) {
+	i.partitionKey = k
+}
+
+func (i *CreateEvent) SetPartition(p int32) {
+	i.partition = &p
+}
+
+func (i *CreateEvent) SetDeliverAfter(t time.Time) {
+	i.deliverAfter = t
+}
+
+func (i *CreateEvent) SetExpiresAt(t time.Time) {
+	i.expiresAt = t
+}
+
+func (i *CreateEvent) SetIdempotencyKey(k string) {
+	i.idempotencyKey = k
+}
+
 func (i *CreateEvent) GetAggregateID() string {
 	return i.aggregateID
 }
@@ -56,3 +105,31 @@ func (i *CreateEvent) GetPayload() json.RawMessage {
 func (i *CreateEvent) GetCreatedAt() time.Time {
 	return i.createdAt
 }
+
+func (i *CreateEvent) GetTraceContext() map[string]string {
+	return i.traceContext
+}
+
+func (i *CreateEvent) GetHeaders() map[string]string {
+	return i.headers
+}
+
+func (i *CreateEvent) GetPartitionKey() string {
+	return i.partitionKey
+}
+
+func (i *CreateEvent) GetPartition() *int32 {
+	return i.partition
+}
+
+func (i *CreateEvent) GetDeliverAfter() time.Time {
+	return i.deliverAfter
+}
+
+func (i *CreateEvent) GetExpiresAt() time.Time {
+	return i.expiresAt
+}
+
+func (i *CreateEvent) GetIdempotencyKey() string {
+	return i.idempotencyKey
+}
@@ -3,18 +3,21 @@ package outbox
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 )
 
 type FailedEvent interface {
 	GetID() string
 	GetType() string
+	GetReservedBy() string
 	GetError() error
 }
 
 type SuccessEvent interface {
 	GetID() string
 	GetType() string
+	GetReservedBy() string
 }
 
 type Event interface {
@@ -23,14 +26,47 @@ type Event interface {
 	GetTopic() string
 	GetType() string
 	GetPayload() json.RawMessage
+	GetReservedBy() string
+	GetCreatedAt() time.Time
+	GetTraceContext() map[string]string
+	GetHeaders() map[string]string
+	GetPartitionKey() string
+	GetPartition() *int32
 }
 
 type Creator interface {
 	CreateEvent(ctx context.Context, d *CreateEvent) (string, error)
+	CreateEvents(ctx context.Context, d []*CreateEvent) ([]string, error)
+	CancelEvent(ctx context.Context, id string) error
+	CancelAggregateEvents(ctx context.Context, aggregateID string) (int, error)
 }
 
 type Adapter interface {
-	ConfirmFailedEvent(ctx context.Context, ev FailedEvent) error
+	ConfirmFailedEvent(ctx context.Context, ev FailedEvent, maxAttempts int, backoff BackoffConfig) error
 	ConfirmEvent(ctx context.Context, ev SuccessEvent) error
-	ReserveNewEvents(ctx context.Context, limit int, reserveDuration time.Duration) ([]Event, error)
+	ReserveNewEvents(ctx context.Context, limit int, reserveDuration time.Duration, ordered bool) ([]Event, error)
+}
+
+// ExpiringAdapter is optional Adapter extension, which moves expired unsent events to expired status
+type ExpiringAdapter interface {
+	ExpireEvents(ctx context.Context, limit int) ([]string, error)
+}
+
+// ErrPublisherFatal is returned by Publish and PublishBatch, if publisher can not be used anymore, outbox is stopped
+var ErrPublisherFatal = errors.New("publisher is in fatal state")
+
+// Publisher sends events to broker, each published event must be reported to Successes or Errors
+type Publisher interface {
+	Publish(ctx context.Context, ev Event) error
+	Successes(ctx context.Context) <-chan Event
+	Errors(ctx context.Context) <-chan *PublishError
+}
+
+// BatchPublisher is used instead of Publish, if Publisher can send whole batch atomically
+type BatchPublisher interface {
+	PublishBatch(ctx context.Context, events []Event) error
+}
+
+type Waker interface {
+	Wake(ctx context.Context) <-chan struct{}
 }
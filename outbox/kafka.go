@@ -3,10 +3,12 @@ package outbox
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/IBM/sarama"
 	"github.com/fedotovmax/kafka-lib/kafka"
+	"go.opentelemetry.io/otel"
 )
 
 type Producer interface {
@@ -15,35 +17,73 @@ type Producer interface {
 	GetErrors() <-chan *sarama.ProducerError
 }
 
-type produceKafka struct {
+type TxProducer interface {
+	Producer
+	IsTransactional() bool
+	BeginTxn() error
+	CommitTxn() error
+	AbortTxn() error
+	TxnStatus() sarama.ProducerTxnStatusFlag
+}
+
+// messageMetadata is used to get event back from producer and to pass exact partition to partitioner
+type messageMetadata struct {
+	Event
+	// transaction sequence number of transactional publisher
+	txn uint64
+}
+
+func (m *messageMetadata) ExplicitPartition() (int32, bool) {
+	p := m.GetPartition()
+	if p == nil {
+		return 0, false
+	}
+	return *p, true
+}
+
+type kafkaPublisher struct {
 	producer Producer
 
 	onceSuccess sync.Once
 	onceErrors  sync.Once
 
-	successes chan *successEvent
-	errors    chan *failedEvent
+	successes chan Event
+	errors    chan *PublishError
 }
 
-func newProduceKafka(p Producer) *produceKafka {
-	return &produceKafka{
+// NewKafkaPublisher creates Publisher over kafka async producer,
+// producer must be created with Return.Successes = true.
+// For transactional producer each batch is published in one kafka transaction
+func NewKafkaPublisher(p Producer) Publisher {
+
+	if tp, ok := p.(TxProducer); ok && tp.IsTransactional() {
+		return newKafkaTxPublisher(tp)
+	}
+
+	return &kafkaPublisher{
 		producer:  p,
-		successes: make(chan *successEvent),
-		errors:    make(chan *failedEvent),
+		successes: make(chan Event),
+		errors:    make(chan *PublishError),
 	}
 }
 
-func (p *produceKafka) Publish(ctx context.Context, ev Event) error {
+func (p *kafkaPublisher) Publish(ctx context.Context, ev Event) error {
 	const op = "outbox.kafka.Publish"
 
-	metadata := &messageMetadata{
-		ID:   ev.GetID(),
-		Type: ev.GetType(),
+	msg := newProducerMessage(ev)
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("%s: event_id: %s: %w", op, ev.GetID(), ctx.Err())
+	case p.producer.GetInput() <- msg:
+		return nil
 	}
+}
 
+func newProducerMessage(ev Event) *sarama.ProducerMessage {
 	msg := &sarama.ProducerMessage{
 		Topic: ev.GetTopic(),
-		Key:   sarama.StringEncoder(ev.GetAggregateID()),
+		Key:   sarama.StringEncoder(ev.GetPartitionKey()),
 		Value: sarama.ByteEncoder(ev.GetPayload()),
 		Headers: []sarama.RecordHeader{
 			{
@@ -55,18 +95,31 @@ func (p *produceKafka) Publish(ctx context.Context, ev Event) error {
 				Value: []byte(ev.GetType()),
 			},
 		},
-		Metadata: metadata,
+		Metadata: &messageMetadata{Event: ev},
 	}
 
-	select {
-	case <-ctx.Done():
-		return fmt.Errorf("%s: event_id: %s: %w", op, ev.GetID(), ctx.Err())
-	case p.producer.GetInput() <- msg:
-		return nil
+	if p := ev.GetPartition(); p != nil {
+		msg.Partition = *p
+	}
+
+	// trace context fields (traceparent, tracestate, baggage) are always taken from event trace context
+	traceFields := otel.GetTextMapPropagator().Fields()
+
+	for k, v := range ev.GetHeaders() {
+		if k == kafka.HeaderEventID || k == kafka.HeaderEventType || slices.Contains(traceFields, k) {
+			continue
+		}
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
 	}
+
+	for k, v := range ev.GetTraceContext() {
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+	}
+
+	return msg
 }
 
-func (p *produceKafka) GetSuccesses(ctx context.Context) <-chan *successEvent {
+func (p *kafkaPublisher) Successes(ctx context.Context) <-chan Event {
 	p.onceSuccess.Do(func() {
 		go func() {
 			defer close(p.successes)
@@ -85,7 +138,7 @@ func (p *produceKafka) GetSuccesses(ctx context.Context) <-chan *successEvent {
 					select {
 					case <-ctx.Done():
 						return
-					case p.successes <- &successEvent{ID: m.ID, Type: m.Type}:
+					case p.successes <- m.Event:
 					}
 				}
 			}
@@ -95,9 +148,9 @@ func (p *produceKafka) GetSuccesses(ctx context.Context) <-chan *successEvent {
 	return p.successes
 }
 
-func (p *produceKafka) GetErrors(ctx context.Context) <-chan *failedEvent {
+func (p *kafkaPublisher) Errors(ctx context.Context) <-chan *PublishError {
 
-	const op = "outbox.kafka.GetErrors"
+	const op = "outbox.kafka.Errors"
 
 	p.onceErrors.Do(func() {
 		go func() {
@@ -117,8 +170,7 @@ func (p *produceKafka) GetErrors(ctx context.Context) <-chan *failedEvent {
 					select {
 					case <-ctx.Done():
 						return
-					case p.errors <- &failedEvent{ID: m.ID, Type: m.Type, Error: fmt.Errorf("%s:%w", op,
-						produceErr.Err)}:
+					case p.errors <- &PublishError{Event: m.Event, Err: fmt.Errorf("%s:%w", op, produceErr.Err)}:
 					}
 				}
 			}
@@ -0,0 +1,244 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/IBM/sarama"
+)
+
+var ErrPublisherNotStarted = errors.New("publisher is not started, Successes and Errors must be called before publishing")
+
+type txResult struct {
+	events []Event
+	err    error
+}
+
+// kafkaTxPublisher publishes each batch in kafka transaction.
+// Events are reported to Successes only after transaction commit,
+// if transaction is aborted, all events of batch are reported to Errors
+type kafkaTxPublisher struct {
+	producer TxProducer
+
+	// one transaction at a time, producer transaction is shared between workers
+	txMu sync.Mutex
+	// sequence number of current transaction, messages are tagged with it
+	txn atomic.Uint64
+
+	onceStart sync.Once
+	started   chan struct{}
+	ctx       context.Context
+
+	errMu sync.Mutex
+	// first produce error of current transaction
+	txErr error
+	// producer can not be used anymore, for example it is fenced by another producer with the same transactional id
+	fatalErr error
+
+	results   chan *txResult
+	successes chan Event
+	errors    chan *PublishError
+}
+
+func newKafkaTxPublisher(p TxProducer) *kafkaTxPublisher {
+	return &kafkaTxPublisher{
+		producer:  p,
+		started:   make(chan struct{}),
+		results:   make(chan *txResult),
+		successes: make(chan Event),
+		errors:    make(chan *PublishError),
+	}
+}
+
+func (p *kafkaTxPublisher) Publish(ctx context.Context, ev Event) error {
+	return p.PublishBatch(ctx, []Event{ev})
+}
+
+// PublishBatch returns ErrPublisherFatal, if producer is in fatal state and must be recreated
+func (p *kafkaTxPublisher) PublishBatch(ctx context.Context, events []Event) error {
+	const op = "outbox.kafka_tx.PublishBatch"
+
+	// results would have no reader, events stay reserved and are published after reserve expiration
+	select {
+	case <-p.started:
+	default:
+		return fmt.Errorf("%s: %w", op, ErrPublisherNotStarted)
+	}
+
+	p.txMu.Lock()
+	defer p.txMu.Unlock()
+
+	if err := p.getFatalErr(); err != nil {
+		return fmt.Errorf("%s: %w: %v", op, ErrPublisherFatal, err)
+	}
+
+	txn := p.txn.Add(1)
+
+	p.errMu.Lock()
+	p.txErr = nil
+	p.errMu.Unlock()
+
+	err := p.producer.BeginTxn()
+
+	if err != nil {
+		err = p.checkFatal(fmt.Errorf("%s: begin txn: %w", op, err))
+		p.report(events, err)
+		return err
+	}
+
+	for _, ev := range events {
+		msg := newProducerMessage(ev)
+		msg.Metadata.(*messageMetadata).txn = txn
+
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("%s: event_id: %s: %w", op, ev.GetID(), ctx.Err())
+			return p.abort(events, err)
+		case p.producer.GetInput() <- msg:
+		}
+	}
+
+	err = p.producer.CommitTxn()
+
+	if err != nil {
+		if txErr := p.getTxErr(); txErr != nil {
+			err = txErr
+		}
+		return p.abort(events, fmt.Errorf("%s: commit txn: %w", op, err))
+	}
+
+	p.report(events, nil)
+
+	return nil
+}
+
+func (p *kafkaTxPublisher) abort(events []Event, err error) error {
+
+	abortErr := p.producer.AbortTxn()
+
+	if abortErr != nil {
+		err = errors.Join(err, fmt.Errorf("abort txn: %w", abortErr))
+	}
+
+	err = p.checkFatal(err)
+
+	p.report(events, err)
+
+	return err
+}
+
+// checkFatal remembers fatal producer state and marks err with ErrPublisherFatal
+func (p *kafkaTxPublisher) checkFatal(err error) error {
+	if p.producer.TxnStatus()&sarama.ProducerTxnFlagFatalError == 0 {
+		return err
+	}
+
+	p.errMu.Lock()
+	if p.fatalErr == nil {
+		p.fatalErr = err
+	}
+	p.errMu.Unlock()
+
+	return fmt.Errorf("%w: %w", ErrPublisherFatal, err)
+}
+
+// report blocks until fanOut receives results, results are dropped only when publisher context is done,
+// then events stay reserved and are published again after reserve expiration
+func (p *kafkaTxPublisher) report(events []Event, err error) {
+	select {
+	case <-p.ctx.Done():
+	case p.results <- &txResult{events: events, err: err}:
+	}
+}
+
+// setTxErr records first produce error of message from current transaction,
+// late errors of already finished transactions are ignored
+func (p *kafkaTxPublisher) setTxErr(txn uint64, err error) {
+	p.errMu.Lock()
+	defer p.errMu.Unlock()
+	if txn == p.txn.Load() && p.txErr == nil {
+		p.txErr = err
+	}
+}
+
+func (p *kafkaTxPublisher) getTxErr() error {
+	p.errMu.Lock()
+	defer p.errMu.Unlock()
+	return p.txErr
+}
+
+func (p *kafkaTxPublisher) getFatalErr() error {
+	p.errMu.Lock()
+	defer p.errMu.Unlock()
+	return p.fatalErr
+}
+
+func (p *kafkaTxPublisher) Successes(ctx context.Context) <-chan Event {
+	p.start(ctx)
+	return p.successes
+}
+
+func (p *kafkaTxPublisher) Errors(ctx context.Context) <-chan *PublishError {
+	p.start(ctx)
+	return p.errors
+}
+
+func (p *kafkaTxPublisher) start(ctx context.Context) {
+	p.onceStart.Do(func() {
+		p.ctx = ctx
+		go p.drain(ctx)
+		go p.fanOut(ctx)
+		close(p.started)
+	})
+}
+
+// drain reads producer channels, results of messages are known only after commit
+func (p *kafkaTxPublisher) drain(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-p.producer.GetSuccesses():
+			if !ok {
+				return
+			}
+		case produceErr, ok := <-p.producer.GetErrors():
+			if !ok {
+				return
+			}
+			if m, ok := produceErr.Msg.Metadata.(*messageMetadata); ok {
+				p.setTxErr(m.txn, produceErr.Err)
+			}
+		}
+	}
+}
+
+func (p *kafkaTxPublisher) fanOut(ctx context.Context) {
+	defer close(p.successes)
+	defer close(p.errors)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case res := <-p.results:
+			for _, ev := range res.events {
+				if res.err == nil {
+					select {
+					case <-ctx.Done():
+						return
+					case p.successes <- ev:
+					}
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case p.errors <- &PublishError{Event: ev, Err: res.err}:
+				}
+			}
+		}
+	}
+}
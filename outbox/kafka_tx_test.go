@@ -0,0 +1,353 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+var errProduce = errors.New("produce error")
+
+// fakeTxProducer handles messages one by one, message results are sent to unbuffered channels.
+// Commit and abort wait until all sent messages are handled, as sarama flushes transaction
+type fakeTxProducer struct {
+	input     chan *sarama.ProducerMessage
+	flush     chan chan struct{}
+	successes chan *sarama.ProducerMessage
+	errors    chan *sarama.ProducerError
+
+	mu sync.Mutex
+	// fail returns produce error for message, nil = message is sent
+	fail func(msg *sarama.ProducerMessage) error
+	// beforeMessage is called before message result is sent
+	beforeMessage func(msg *sarama.ProducerMessage)
+	beginErr      error
+	status        sarama.ProducerTxnStatusFlag
+	txnFailed     bool
+	begins        int
+	commits       int
+	aborts        int
+}
+
+func newFakeTxProducer(t *testing.T) *fakeTxProducer {
+	p := &fakeTxProducer{
+		input:     make(chan *sarama.ProducerMessage),
+		flush:     make(chan chan struct{}),
+		successes: make(chan *sarama.ProducerMessage),
+		errors:    make(chan *sarama.ProducerError),
+		status:    sarama.ProducerTxnFlagReady,
+	}
+
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case ack := <-p.flush:
+				close(ack)
+			case msg := <-p.input:
+				p.mu.Lock()
+				fail, before := p.fail, p.beforeMessage
+				p.mu.Unlock()
+
+				if before != nil {
+					before(msg)
+				}
+
+				var err error
+				if fail != nil {
+					err = fail(msg)
+				}
+
+				if err != nil {
+					p.mu.Lock()
+					p.txnFailed = true
+					p.mu.Unlock()
+					select {
+					case <-done:
+						return
+					case p.errors <- &sarama.ProducerError{Msg: msg, Err: err}:
+					}
+					continue
+				}
+
+				select {
+				case <-done:
+					return
+				case p.successes <- msg:
+				}
+			}
+		}
+	}()
+
+	return p
+}
+
+func (p *fakeTxProducer) GetInput() chan<- *sarama.ProducerMessage {
+	return p.input
+}
+
+func (p *fakeTxProducer) GetSuccesses() <-chan *sarama.ProducerMessage {
+	return p.successes
+}
+
+func (p *fakeTxProducer) GetErrors() <-chan *sarama.ProducerError {
+	return p.errors
+}
+
+func (p *fakeTxProducer) IsTransactional() bool {
+	return true
+}
+
+func (p *fakeTxProducer) BeginTxn() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.begins++
+	p.txnFailed = false
+	return p.beginErr
+}
+
+func (p *fakeTxProducer) waitFlush() {
+	ack := make(chan struct{})
+	p.flush <- ack
+	<-ack
+}
+
+func (p *fakeTxProducer) CommitTxn() error {
+	p.waitFlush()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.txnFailed {
+		return errors.New("transaction has failed messages")
+	}
+	p.commits++
+	return nil
+}
+
+func (p *fakeTxProducer) AbortTxn() error {
+	p.waitFlush()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.aborts++
+	return nil
+}
+
+func (p *fakeTxProducer) TxnStatus() sarama.ProducerTxnStatusFlag {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.status
+}
+
+func testEvents(ids ...string) []Event {
+	events := make([]Event, 0, len(ids))
+	for _, id := range ids {
+		events = append(events, &EventModel{ID: id, AggregateID: "aggregate-" + id, Topic: "orders", Type: "order.created"})
+	}
+	return events
+}
+
+// collect reads n results of publisher
+func collect(t *testing.T, successes <-chan Event, errs <-chan *PublishError, n int) ([]string, []*PublishError) {
+	t.Helper()
+
+	var sent []string
+	var failed []*PublishError
+
+	timeout := time.After(2 * time.Second)
+
+	for len(sent)+len(failed) < n {
+		select {
+		case ev := <-successes:
+			sent = append(sent, ev.GetID())
+		case perr := <-errs:
+			failed = append(failed, perr)
+		case <-timeout:
+			t.Fatalf("got %d of %d results", len(sent)+len(failed), n)
+		}
+	}
+
+	return sent, failed
+}
+
+func TestKafkaTxPublisherBatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		fail        func(msg *sarama.ProducerMessage) error
+		wantErr     bool
+		wantSent    []string
+		wantFailed  []string
+		wantCommits int
+		wantAborts  int
+	}{
+		{
+			name:        "commit reports all events to successes",
+			wantSent:    []string{"1", "2", "3"},
+			wantCommits: 1,
+		},
+		{
+			name: "produce error aborts whole batch",
+			fail: func(msg *sarama.ProducerMessage) error {
+				if msg.Metadata.(*messageMetadata).GetID() == "2" {
+					return errProduce
+				}
+				return nil
+			},
+			wantErr:    true,
+			wantFailed: []string{"1", "2", "3"},
+			wantAborts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			producer := newFakeTxProducer(t)
+			producer.fail = tt.fail
+
+			p := NewKafkaPublisher(producer)
+
+			successes, errs := p.Successes(ctx), p.Errors(ctx)
+
+			bp, ok := p.(BatchPublisher)
+			if !ok {
+				t.Fatal("transactional publisher must implement BatchPublisher")
+			}
+
+			done := make(chan error, 1)
+			go func() {
+				done <- bp.PublishBatch(ctx, testEvents("1", "2", "3"))
+			}()
+
+			sent, failed := collect(t, successes, errs, 3)
+
+			if err := <-done; (err != nil) != tt.wantErr {
+				t.Fatalf("PublishBatch error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if !slices.Equal(sent, tt.wantSent) {
+				t.Errorf("sent = %v, want %v", sent, tt.wantSent)
+			}
+
+			var failedIDs []string
+			for _, perr := range failed {
+				failedIDs = append(failedIDs, perr.Event.GetID())
+				if !errors.Is(perr, errProduce) {
+					t.Errorf("event %s error = %v, want produce error", perr.Event.GetID(), perr.Err)
+				}
+			}
+
+			if !slices.Equal(failedIDs, tt.wantFailed) {
+				t.Errorf("failed = %v, want %v", failedIDs, tt.wantFailed)
+			}
+
+			if producer.commits != tt.wantCommits || producer.aborts != tt.wantAborts {
+				t.Errorf("commits/aborts = %d/%d, want %d/%d", producer.commits, producer.aborts, tt.wantCommits, tt.wantAborts)
+			}
+		})
+	}
+}
+
+func TestKafkaTxPublisherIgnoresLateErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	producer := newFakeTxProducer(t)
+
+	p := newKafkaTxPublisher(producer)
+
+	successes, errs := p.Successes(ctx), p.Errors(ctx)
+
+	var firstTxnMsg *sarama.ProducerMessage
+
+	producer.beforeMessage = func(msg *sarama.ProducerMessage) {
+		if firstTxnMsg == nil {
+			firstTxnMsg = msg
+		}
+	}
+
+	go func() { _ = p.PublishBatch(ctx, testEvents("1")) }()
+
+	if sent, _ := collect(t, successes, errs, 1); !slices.Equal(sent, []string{"1"}) {
+		t.Fatalf("first batch sent = %v, want [1]", sent)
+	}
+
+	// error of message from first transaction arrives while second transaction is in progress,
+	// drain handles it before result of second message, because producer channels are unbuffered
+	producer.mu.Lock()
+	producer.beforeMessage = func(msg *sarama.ProducerMessage) {
+		producer.errors <- &sarama.ProducerError{Msg: firstTxnMsg, Err: errProduce}
+	}
+	producer.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() { done <- p.PublishBatch(ctx, testEvents("2")) }()
+
+	sent, failed := collect(t, successes, errs, 1)
+
+	if err := <-done; err != nil {
+		t.Fatalf("PublishBatch error = %v, want nil", err)
+	}
+
+	if !slices.Equal(sent, []string{"2"}) || len(failed) != 0 {
+		t.Fatalf("second batch sent = %v, failed = %d, want [2] and no failures", sent, len(failed))
+	}
+}
+
+func TestKafkaTxPublisherNotStarted(t *testing.T) {
+	producer := newFakeTxProducer(t)
+
+	err := newKafkaTxPublisher(producer).PublishBatch(context.Background(), testEvents("1"))
+
+	if !errors.Is(err, ErrPublisherNotStarted) {
+		t.Fatalf("error = %v, want ErrPublisherNotStarted", err)
+	}
+
+	if producer.begins != 0 {
+		t.Errorf("transaction is started before publisher")
+	}
+}
+
+func TestKafkaTxPublisherFatalStopsOutbox(t *testing.T) {
+	producer := newFakeTxProducer(t)
+	producer.beginErr = sarama.ErrProducerFenced
+	producer.status = sarama.ProducerTxnFlagInError | sarama.ProducerTxnFlagFatalError
+
+	ad := &fakeAdapter{reserve: func(ctx context.Context, limit int) ([]Event, error) {
+		return testEvents("1"), nil
+	}}
+
+	p := NewKafkaPublisher(producer)
+
+	a, err := New(discardLogger(), p, ad, testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.Start()
+
+	select {
+	case <-a.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("outbox is not stopped after fatal publisher error")
+	}
+
+	err = p.(BatchPublisher).PublishBatch(context.Background(), testEvents("2"))
+
+	if !errors.Is(err, ErrPublisherFatal) {
+		t.Fatalf("error = %v, want ErrPublisherFatal", err)
+	}
+
+	if producer.begins != 1 {
+		t.Errorf("begins = %d, want 1, fatal publisher must not start new transactions", producer.begins)
+	}
+}
@@ -0,0 +1,34 @@
+package outbox
+
+import "time"
+
+// Metrics receives measurements of outbox relay, methods are called concurrently
+type Metrics interface {
+	// Events reserved by one processing
+	EventsReserved(n int)
+	// Events passed to publisher by one processing
+	EventsPublished(n int)
+	// Event is acked by broker, lag - time from event created_at to ack
+	EventAcked(lag time.Duration)
+	// Event is rejected by broker
+	EventFailed()
+	// Error when confirm send or send fail in adapter
+	ConfirmError()
+	// Unsent events moved to expired status
+	EventsExpired(n int)
+	// Reservation expired and event was reserved again, confirm skipped
+	ReservationLost()
+	// Duration of processing non-empty batch: reserve and publish
+	BatchDuration(d time.Duration)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) EventsReserved(int)          {}
+func (noopMetrics) EventsPublished(int)         {}
+func (noopMetrics) EventAcked(time.Duration)    {}
+func (noopMetrics) EventFailed()                {}
+func (noopMetrics) ConfirmError()               {}
+func (noopMetrics) EventsExpired(int)           {}
+func (noopMetrics) ReservationLost()            {}
+func (noopMetrics) BatchDuration(time.Duration) {}
@@ -2,12 +2,18 @@ package outbox
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
 type successEvent struct {
-	ID   string
-	Type string
+	ID         string
+	Type       string
+	ReservedBy string
+}
+
+func newSuccessEvent(ev Event) *successEvent {
+	return &successEvent{ID: ev.GetID(), Type: ev.GetType(), ReservedBy: ev.GetReservedBy()}
 }
 
 func (se *successEvent) GetID() string {
@@ -18,10 +24,19 @@ func (se *successEvent) GetType() string {
 	return se.Type
 }
 
+func (se *successEvent) GetReservedBy() string {
+	return se.ReservedBy
+}
+
 type failedEvent struct {
-	ID    string
-	Type  string
-	Error error
+	ID         string
+	Type       string
+	ReservedBy string
+	Error      error
+}
+
+func newFailedEvent(ev Event, err error) *failedEvent {
+	return &failedEvent{ID: ev.GetID(), Type: ev.GetType(), ReservedBy: ev.GetReservedBy(), Error: err}
 }
 
 func (fe *failedEvent) GetID() string {
@@ -32,13 +47,25 @@ func (fe *failedEvent) GetType() string {
 	return fe.Type
 }
 
+func (fe *failedEvent) GetReservedBy() string {
+	return fe.ReservedBy
+}
+
 func (fe *failedEvent) GetError() error {
 	return fe.Error
 }
 
-type messageMetadata struct {
-	ID   string
-	Type string
+type PublishError struct {
+	Event Event
+	Err   error
+}
+
+func (pe *PublishError) Error() string {
+	return fmt.Sprintf("event_id: %s: %v", pe.Event.GetID(), pe.Err)
+}
+
+func (pe *PublishError) Unwrap() error {
+	return pe.Err
 }
 
 type EventStatus string
@@ -49,14 +76,78 @@ func (es EventStatus) String() string {
 
 const EventStatusNew EventStatus = "new"
 const EventStatusDone EventStatus = "done"
+const EventStatusFailed EventStatus = "failed"
+const EventStatusExpired EventStatus = "expired"
+const EventStatusCancelled EventStatus = "cancelled"
 
 type EventModel struct {
-	ID          string
-	AggregateID string
-	Topic       string
-	Type        string
-	Payload     json.RawMessage
-	Status      EventStatus
-	CreatedAt   time.Time
-	ReservedTo  *time.Time
+	ID            string
+	AggregateID   string
+	Topic         string
+	Type          string
+	Payload       json.RawMessage
+	Status        EventStatus
+	CreatedAt     time.Time
+	ReservedTo    *time.Time
+	ReservedBy    *string
+	Attempts      int
+	LastError     *string
+	NextAttemptAt *time.Time
+	TraceContext  map[string]string
+	Headers       map[string]string
+	PartitionKey  *string
+	Partition     *int32
+	DeliverAfter  *time.Time
+	ExpiresAt     *time.Time
+}
+
+func (e *EventModel) GetID() string {
+	return e.ID
+}
+
+func (e *EventModel) GetAggregateID() string {
+	return e.AggregateID
+}
+
+func (e *EventModel) GetTopic() string {
+	return e.Topic
+}
+
+func (e *EventModel) GetType() string {
+	return e.Type
+}
+
+func (e *EventModel) GetPayload() json.RawMessage {
+	return e.Payload
+}
+
+func (e *EventModel) GetTraceContext() map[string]string {
+	return e.TraceContext
+}
+
+func (e *EventModel) GetHeaders() map[string]string {
+	return e.Headers
+}
+
+// GetPartitionKey returns partition key, if it is set, otherwise aggregate id
+func (e *EventModel) GetPartitionKey() string {
+	if e.PartitionKey == nil || *e.PartitionKey == "" {
+		return e.AggregateID
+	}
+	return *e.PartitionKey
+}
+
+func (e *EventModel) GetPartition() *int32 {
+	return e.Partition
+}
+
+func (e *EventModel) GetCreatedAt() time.Time {
+	return e.CreatedAt
+}
+
+func (e *EventModel) GetReservedBy() string {
+	if e.ReservedBy == nil {
+		return ""
+	}
+	return *e.ReservedBy
 }
@@ -0,0 +1,18 @@
+package outbox
+
+type Option func(*Outbox)
+
+// WithWaker sets source of wake-ups, on each wake-up one of workers starts processing immediately.
+// Interval ticker is still used as fallback
+func WithWaker(w Waker) Option {
+	return func(a *Outbox) {
+		a.waker = w
+	}
+}
+
+// WithMetrics sets receiver of outbox relay metrics
+func WithMetrics(m Metrics) Option {
+	return func(a *Outbox) {
+		a.metrics = m
+	}
+}
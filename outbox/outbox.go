@@ -5,25 +5,27 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
-
-	"github.com/fedotovmax/kafka-lib/kafka"
 )
 
 type Outbox struct {
-	kafka     *produceKafka
+	publisher Publisher
 	log       *slog.Logger
 	cfg       *Config
 	adapter   Adapter
+	waker     Waker
+	metrics   Metrics
 	ctx       context.Context
 	stop      context.CancelFunc
 	isStopped chan struct{}
-	inProcess int32
 }
 
 // Limit = 50, ProcessTimeout = 360ms -> For kafka flush: MaxMessages = 12-25, Frequency = 90-180ms;
 // Limit = 200, ProcessTimeout = 530ms -> For kafka flush: MaxMessages = 50-100, Frequency = 130-265ms;
 // Limit = 500, ProcessTimeout = 720ms -> For kafka flush: MaxMessages = 125-250, Frequency = 180-360ms;
-func New(l *slog.Logger, p kafka.Producer, ad Adapter, cfg *Config) (*Outbox, error) {
+func New(l *slog.Logger, p Publisher, ad Adapter, cfg *Config, opts ...Option) (*Outbox, error) {
+
+	c := cfg.withDefaults()
+	cfg = &c
 
 	err := validateConfig(cfg)
 
@@ -33,17 +35,22 @@ func New(l *slog.Logger, p kafka.Producer, ad Adapter, cfg *Config) (*Outbox, er
 
 	ctx, cancel := context.WithCancel(context.Background())
 
-	kafka := newProduceKafka(p)
-
-	return &Outbox{
-		kafka:     kafka,
+	a := &Outbox{
+		publisher: p,
 		log:       l,
 		adapter:   ad,
+		metrics:   noopMetrics{},
 		cfg:       cfg,
 		ctx:       ctx,
 		stop:      cancel,
 		isStopped: make(chan struct{}),
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a, nil
 }
 
 func (a *Outbox) Start() {
@@ -53,6 +60,7 @@ func (a *Outbox) Start() {
 	a.successesMonitoring(wg)
 	a.errorsMonitoring(wg)
 	a.processingNewEvents(wg)
+	a.expiringEvents(wg)
 
 	go func() {
 		wg.Wait()
@@ -60,6 +68,11 @@ func (a *Outbox) Start() {
 	}()
 }
 
+// Done is closed when outbox is stopped, including stop after fatal publisher error
+func (a *Outbox) Done() <-chan struct{} {
+	return a.isStopped
+}
+
 func (a *Outbox) Stop(ctx context.Context) error {
 	const op = "outbox.app.Stop"
 	log := a.log.With(slog.String("op", op))
@@ -2,37 +2,81 @@ package outbox
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"time"
 )
 
-func (a *Outbox) process() {
+// process returns num of reserved events
+func (a *Outbox) process() int {
 
 	const op = "outbox.process"
 
 	log := a.log.With(slog.String("op", op))
 
+	start := time.Now()
+
 	queriesCtx, cancelQueriesCtx := context.WithTimeout(a.ctx, a.cfg.ProcessTimeout)
 	defer cancelQueriesCtx()
 
-	events, err := a.adapter.ReserveNewEvents(queriesCtx, a.cfg.Limit, a.cfg.ReserveDuration)
+	events, err := a.adapter.ReserveNewEvents(queriesCtx, a.cfg.Limit, a.cfg.ReserveDuration, a.cfg.Ordered)
 
 	if err != nil {
 		log.Error("error when processing", slog.String("error", err.Error()))
-		return
+		return 0
 	}
 
 	if len(events) == 0 {
 		log.Debug("skip processing, no new events")
-		return
+		return 0
 	}
 
+	a.metrics.EventsReserved(len(events))
+	defer func() {
+		a.metrics.BatchDuration(time.Since(start))
+	}()
+
 	publishCtx, cancelPublishCtx := context.WithTimeout(a.ctx, a.cfg.ProcessTimeout)
 	defer cancelPublishCtx()
 
+	if bp, ok := a.publisher.(BatchPublisher); ok {
+		err := bp.PublishBatch(publishCtx, events)
+		if err != nil {
+			log.Error("publish batch error", slog.String("error", err.Error()))
+			a.stopOnFatal(log, err)
+			return len(events)
+		}
+		a.metrics.EventsPublished(len(events))
+		return len(events)
+	}
+
+	var published int
+
 	for _, event := range events {
-		err := a.kafka.Publish(publishCtx, event)
+		err := a.publisher.Publish(publishCtx, event)
 		if err != nil {
 			log.Error("publish error", slog.String("error", err.Error()))
+			if a.stopOnFatal(log, err) {
+				break
+			}
+			continue
 		}
+		published++
 	}
+
+	a.metrics.EventsPublished(published)
+
+	return len(events)
+}
+
+// stopOnFatal stops outbox, if publisher can not be used anymore
+func (a *Outbox) stopOnFatal(log *slog.Logger, err error) bool {
+	if !errors.Is(err, ErrPublisherFatal) {
+		return false
+	}
+
+	log.Error("publisher is in fatal state, outbox is stopping")
+	a.stop()
+
+	return true
 }
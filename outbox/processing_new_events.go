@@ -3,32 +3,72 @@ package outbox
 import (
 	"log/slog"
 	"sync"
-	"sync/atomic"
 	"time"
 )
 
 func (a *Outbox) processingNewEvents(wg *sync.WaitGroup) {
-	const op = "outbox.processingNewEvents"
 
-	log := a.log.With(slog.String("op", op))
+	var wake <-chan struct{}
+
+	if a.waker != nil {
+		wake = a.waker.Wake(a.ctx)
+	}
+
+	for i := 0; i < a.cfg.Workers; i++ {
+		a.worker(wg, i, wake)
+	}
+}
+
+func (a *Outbox) worker(wg *sync.WaitGroup, num int, wake <-chan struct{}) {
+	const op = "outbox.worker"
+
+	log := a.log.With(slog.String("op", op), slog.Int("worker", num))
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		ticker := time.NewTicker(a.cfg.Interval)
-		defer ticker.Stop()
+		interval := a.cfg.Interval
+		timer := time.NewTimer(interval)
+		defer timer.Stop()
 		for {
+			var reserved int
 			select {
 			case <-a.ctx.Done():
 				log.Info("event processing stopped")
 				return
-			case <-ticker.C:
-				if !atomic.CompareAndSwapInt32(&a.inProcess, 0, 1) {
+			case _, ok := <-wake:
+				if !ok {
+					// waker closes channel on shutdown too
+					if a.ctx.Err() != nil {
+						log.Info("event processing stopped")
+						return
+					}
+					log.Warn("waker channel closed, continue with interval only")
+					wake = nil
 					continue
 				}
-				a.process()
-				atomic.StoreInt32(&a.inProcess, 0)
+				reserved = a.process()
+			case <-timer.C:
+				reserved = a.process()
 			}
+			interval = a.nextInterval(interval, reserved)
+			timer.Reset(interval)
 		}
 	}()
 }
+
+func (a *Outbox) nextInterval(current time.Duration, reserved int) time.Duration {
+
+	if a.cfg.MaxInterval == 0 {
+		return a.cfg.Interval
+	}
+
+	switch {
+	case reserved >= a.cfg.Limit:
+		return 0
+	case reserved == 0:
+		return min(max(current*2, a.cfg.Interval), a.cfg.MaxInterval)
+	default:
+		return a.cfg.Interval
+	}
+}
@@ -0,0 +1,115 @@
+package outbox
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkersReserveConcurrently(t *testing.T) {
+	tests := []struct {
+		name    string
+		workers int
+	}{
+		{name: "one worker", workers: 1},
+		{name: "four workers", workers: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var active, maxActive atomic.Int32
+
+			release := make(chan struct{})
+
+			ad := &fakeAdapter{reserve: func(ctx context.Context, limit int) ([]Event, error) {
+				n := active.Add(1)
+				defer active.Add(-1)
+
+				for {
+					m := maxActive.Load()
+					if n <= m || maxActive.CompareAndSwap(m, n) {
+						break
+					}
+				}
+
+				select {
+				case <-release:
+				case <-ctx.Done():
+				}
+
+				return nil, nil
+			}}
+
+			cfg := testConfig()
+			cfg.Workers = tt.workers
+
+			a, err := New(discardLogger(), newFakePublisher(), ad, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			a.Start()
+
+			deadline := time.After(2 * time.Second)
+
+			for maxActive.Load() < int32(tt.workers) {
+				select {
+				case <-deadline:
+					t.Fatalf("max concurrent reservations = %d, want %d", maxActive.Load(), tt.workers)
+				case <-time.After(10 * time.Millisecond):
+				}
+			}
+
+			// no more reservations than workers
+			time.Sleep(200 * time.Millisecond)
+
+			if got := maxActive.Load(); got != int32(tt.workers) {
+				t.Errorf("max concurrent reservations = %d, want %d", got, tt.workers)
+			}
+
+			close(release)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			if err = a.Stop(ctx); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestNextInterval(t *testing.T) {
+	const (
+		interval    = 250 * time.Millisecond
+		maxInterval = 2 * time.Second
+		limit       = 50
+	)
+
+	tests := []struct {
+		name        string
+		maxInterval time.Duration
+		current     time.Duration
+		reserved    int
+		want        time.Duration
+	}{
+		{name: "adaptive disabled, full batch", current: interval, reserved: limit, want: interval},
+		{name: "adaptive disabled, empty batch", current: interval, reserved: 0, want: interval},
+		{name: "full batch polls immediately", maxInterval: maxInterval, current: interval, reserved: limit, want: 0},
+		{name: "empty batch doubles interval", maxInterval: maxInterval, current: interval, reserved: 0, want: 2 * interval},
+		{name: "empty batch after immediate poll", maxInterval: maxInterval, current: 0, reserved: 0, want: interval},
+		{name: "empty batch limited by max", maxInterval: maxInterval, current: 1500 * time.Millisecond, reserved: 0, want: maxInterval},
+		{name: "partial batch resets interval", maxInterval: maxInterval, current: maxInterval, reserved: 10, want: interval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Outbox{cfg: &Config{Limit: limit, Interval: interval, MaxInterval: tt.maxInterval}}
+
+			if got := a.nextInterval(tt.current, tt.reserved); got != tt.want {
+				t.Errorf("nextInterval(%s, %d) = %s, want %s", tt.current, tt.reserved, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+var ErrEventNotRegistered = errors.New("event type is not registered")
+
+type registeredEvent struct {
+	topic string
+	ttype string
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[reflect.Type]registeredEvent{}
+)
+
+// Register binds payload type T to topic and event type, usually called once on service start.
+// Repeated call for the same T replaces previous binding
+func Register[T any](topic, eventType string) error {
+	const op = "outbox.Register"
+
+	if topic == "" || eventType == "" {
+		return fmt.Errorf("%s: topic and event type are required", op)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[reflect.TypeFor[T]()] = registeredEvent{topic: topic, ttype: eventType}
+
+	return nil
+}
+
+// NewEvent builds CreateEvent with topic and type registered for T, payload is marshalled to json
+func NewEvent[T any](aggregateID string, payload T) (*CreateEvent, error) {
+	const op = "outbox.NewEvent"
+
+	t := reflect.TypeFor[T]()
+
+	registryMu.RLock()
+	reg, ok := registry[t]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%s: %w: %s", op, ErrEventNotRegistered, t)
+	}
+
+	b, err := json.Marshal(payload)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ev := NewCreateEventInput()
+	ev.SetAggregateID(aggregateID)
+	ev.SetTopic(reg.topic)
+	ev.SetType(reg.ttype)
+	ev.SetPayload(b)
+	ev.SetCreatedAt(time.Now().UTC())
+
+	return ev, nil
+}
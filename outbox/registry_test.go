@@ -0,0 +1,100 @@
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// every test uses its own payload types, registry is global
+type notRegisteredPayload struct{}
+
+type emptyBindingPayload struct{}
+
+type reRegisteredPayload struct{}
+
+type orderCreatedPayload struct {
+	ID    string `json:"id"`
+	Total int    `json:"total"`
+}
+
+func TestNewEventNotRegistered(t *testing.T) {
+	_, err := NewEvent("agg-1", notRegisteredPayload{})
+
+	if !errors.Is(err, ErrEventNotRegistered) {
+		t.Fatalf("err = %v, want ErrEventNotRegistered", err)
+	}
+}
+
+func TestRegisterRequiresTopicAndType(t *testing.T) {
+	if err := Register[emptyBindingPayload]("", "order.created"); err == nil {
+		t.Errorf("empty topic is accepted")
+	}
+
+	if err := Register[emptyBindingPayload]("orders", ""); err == nil {
+		t.Errorf("empty event type is accepted")
+	}
+
+	if _, err := NewEvent("agg-1", emptyBindingPayload{}); !errors.Is(err, ErrEventNotRegistered) {
+		t.Errorf("rejected binding is registered, err = %v", err)
+	}
+}
+
+func TestRegisterReplacesBinding(t *testing.T) {
+	if err := Register[reRegisteredPayload]("orders", "order.created"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Register[reRegisteredPayload]("orders-v2", "order.created.v2"); err != nil {
+		t.Fatal(err)
+	}
+
+	ev, err := NewEvent("agg-1", reRegisteredPayload{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ev.GetTopic() != "orders-v2" || ev.GetType() != "order.created.v2" {
+		t.Errorf("binding = %s/%s, want orders-v2/order.created.v2", ev.GetTopic(), ev.GetType())
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	if err := Register[orderCreatedPayload]("orders", "order.created"); err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now()
+
+	ev, err := NewEvent("agg-1", orderCreatedPayload{ID: "o-1", Total: 42})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ev.GetAggregateID() != "agg-1" {
+		t.Errorf("aggregate id = %q, want agg-1", ev.GetAggregateID())
+	}
+
+	if ev.GetTopic() != "orders" || ev.GetType() != "order.created" {
+		t.Errorf("binding = %s/%s, want orders/order.created", ev.GetTopic(), ev.GetType())
+	}
+
+	var payload orderCreatedPayload
+
+	if err := json.Unmarshal(ev.GetPayload(), &payload); err != nil {
+		t.Fatalf("payload is not json: %v", err)
+	}
+
+	if payload != (orderCreatedPayload{ID: "o-1", Total: 42}) {
+		t.Errorf("payload = %+v", payload)
+	}
+
+	createdAt := ev.GetCreatedAt()
+
+	if createdAt.Before(before.Add(-time.Second)) || createdAt.After(time.Now()) {
+		t.Errorf("createdAt = %s, want around %s", createdAt, before)
+	}
+}
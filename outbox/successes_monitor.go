@@ -1,8 +1,12 @@
 package outbox
 
 import (
+	"errors"
 	"log/slog"
 	"sync"
+	"time"
+
+	kafkalib "github.com/fedotovmax/kafka-lib"
 )
 
 func (a *Outbox) successesMonitoring(wg *sync.WaitGroup) {
@@ -10,7 +14,7 @@ func (a *Outbox) successesMonitoring(wg *sync.WaitGroup) {
 
 	log := a.log.With(slog.String("op", op))
 
-	eventsSuccesses := a.kafka.GetSuccesses(a.ctx)
+	eventsSuccesses := a.publisher.Successes(a.ctx)
 
 	wg.Add(1)
 	go func() {
@@ -25,13 +29,21 @@ func (a *Outbox) successesMonitoring(wg *sync.WaitGroup) {
 					log.Info("monitoring [successes] stopped: channel closed")
 					return
 				}
-				err := a.confirm(event)
+				a.metrics.EventAcked(time.Since(event.GetCreatedAt()))
+				err := a.confirm(newSuccessEvent(event))
+				if errors.Is(err, kafkalib.ErrMissUpdate) {
+					a.metrics.ReservationLost()
+					log.Warn("event sended, but reservation is lost, confirm skipped",
+						slog.String("event_id", event.GetID()))
+					continue
+				}
 				if err != nil {
+					a.metrics.ConfirmError()
 					log.Error("error when confirm event, but event is sended",
 						slog.String("error", err.Error()))
 					continue
 				}
-				log.Info("event sended", slog.String("event_id", event.ID))
+				log.Info("event sended", slog.String("event_id", event.GetID()))
 			}
 		}
 	}()